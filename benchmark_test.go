@@ -94,3 +94,34 @@ func BenchmarkDecodeHex(b *testing.B) {
 		_ = dst[:n]
 	}
 }
+
+func BenchmarkEncodeConstantTime(b *testing.B) {
+	decoded := []byte{
+		0xc0, 0x73, 0x62, 0x4a, 0xaf, 0x39, 0x78, 0x51,
+		0x4e, 0xf8, 0x44, 0x3b, 0xb2, 0xa8, 0x59, 0xc7,
+		0x5f, 0xc3, 0xcc, 0x6a, 0xf2, 0x6d, 0x5a, 0xaa,
+	}
+	encoding := zrockford32.StdEncoding.WithConstantTime()
+	dst := make([]byte, encoding.EncodedLen(len(decoded)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		n := encoding.Encode(dst, decoded)
+		_ = dst[:n]
+	}
+}
+
+func BenchmarkDecodeConstantTime(b *testing.B) {
+	encoded := []byte("AB3SR12X8FHFNVZAE075FKN3A7XH8VDK6JS22K0")
+	encoding := zrockford32.StdEncoding.WithConstantTime()
+	dst := make([]byte, encoding.DecodedLen(len(encoded)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		n, err := encoding.Decode(dst, encoded)
+		if err != nil {
+			b.Fatalf("decode error: %v", err)
+		}
+		_ = dst[:n]
+	}
+}
@@ -0,0 +1,88 @@
+package zrockford32
+
+import "strings"
+
+// Blocklist
+
+// Blocklist rejects codes spelling unwanted words. Matching is case-insensitive,
+// ignores group separators and reads the digits of the alphabets the way they
+// are commonly used in leetspeak, so that "5H17" is caught by "shit".
+type Blocklist struct {
+	words []string
+}
+
+var leetspeak = map[byte]string{
+	'0': "o",
+	'1': "il",
+	'2': "z",
+	'3': "e",
+	'4': "a",
+	'5': "s",
+	'6': "gb",
+	'7': "t",
+	'8': "b",
+	'9': "g",
+	'v': "u",
+}
+
+var defaultBlockedWords = []string{
+	"anal", "anus", "arse", "ass", "bastard", "bitch", "bollock", "boner",
+	"boob", "butt", "clit", "cock", "coon", "crap", "cum", "cunt", "damn",
+	"dick", "dildo", "dyke", "fag", "fuck", "homo", "jizz", "kike", "kkk",
+	"nazi", "nigg", "penis", "piss", "poop", "porn", "prick", "pube", "pussy",
+	"rape", "retard", "sex", "shit", "slut", "spic", "tit", "twat", "vagina",
+	"wank", "whore", "wtf",
+}
+
+var DefaultBlocklist = NewBlocklist(defaultBlockedWords...)
+
+func NewBlocklist(words ...string) *Blocklist {
+	b := new(Blocklist)
+
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if len(word) > 0 {
+			b.words = append(b.words, word)
+		}
+	}
+
+	return b
+}
+
+// Match reports whether code spells one of the blocked words.
+func (b *Blocklist) Match(code string) bool {
+	if b == nil {
+		return false
+	}
+
+	normalized := make([]byte, 0, len(code))
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') {
+			normalized = append(normalized, c)
+		}
+	}
+
+	for _, word := range b.words {
+		for i := 0; i+len(word) <= len(normalized); i++ {
+			if leetMatch(normalized[i:i+len(word)], word) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func leetMatch(code []byte, word string) bool {
+	for i := 0; i < len(word); i++ {
+		if code[i] != word[i] && strings.IndexByte(leetspeak[code[i]], word[i]) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
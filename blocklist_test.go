@@ -0,0 +1,50 @@
+package zrockford32_test
+
+import (
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func TestBlocklistMatch(t *testing.T) {
+	b := zrockford32.NewBlocklist("shit", " Bad ", "")
+	for _, tc := range []struct {
+		code    string
+		blocked bool
+	}{
+		{"", false},
+		{"SHIT", true},
+		{"ybshity", true},
+		{"5H17", true},
+		{"YY5H1TYY", true},
+		{"S-H-1-T", true},
+		{"8AD", true},
+		{"84DY", true},
+		{"SH1", false},
+		{"YBNDRFG8", false},
+	} {
+		if g, e := b.Match(tc.code), tc.blocked; g != e {
+			t.Errorf("Match %q wrong result: %v != %v", tc.code, g, e)
+		}
+	}
+}
+
+func TestBlocklistNil(t *testing.T) {
+	var b *zrockford32.Blocklist
+	if b.Match("5H17") {
+		t.Errorf("nil blocklist matched")
+	}
+}
+
+func TestDefaultBlocklist(t *testing.T) {
+	for _, code := range []string{"PR1CK", "W4NK", "C0CK", "FVCK", "D4MN"} {
+		if !zrockford32.DefaultBlocklist.Match(code) {
+			t.Errorf("DefaultBlocklist did not match %q", code)
+		}
+	}
+	for _, code := range []string{"YBNDRFG8", "MQ3XK2ZW"} {
+		if zrockford32.DefaultBlocklist.Match(code) {
+			t.Errorf("DefaultBlocklist matched %q", code)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package zrockford32
+
+import (
+	"errors"
+	"strings"
+)
+
+// Check symbols
+//
+// A check symbol is appended to a code so that typos can be detected before
+// the code is used. Each symbol is weighted by a distinct power of a generator
+// of GF(32), which catches every single substituted symbol and every swap of
+// two adjacent, different symbols.
+
+var ErrCheckSymbol = errors.New("zrockford32 check symbol mismatch")
+
+func (e *Encoding) CheckSymbol(code string) (byte, error) {
+	sum := byte(0)
+
+	for i := 0; i < len(code); i++ {
+		d := e.decodeMap[code[i]]
+		if d > 31 {
+			return 0, CorruptInputError(i)
+		}
+		sum ^= gf32Mul(d, gf32Exp[(i+1)%31])
+	}
+
+	return e.encoder[sum], nil
+}
+
+func (e *Encoding) AddCheckSymbol(code string) (string, error) {
+	check, err := e.CheckSymbol(code)
+	if err != nil {
+		return "", err
+	}
+
+	return code + string(check), nil
+}
+
+// VerifyCheckSymbol returns code without its trailing check symbol.
+func (e *Encoding) VerifyCheckSymbol(code string) (string, error) {
+	if len(code) == 0 {
+		return "", CorruptInputError(0)
+	}
+
+	payload := code[:len(code)-1]
+	check, err := e.CheckSymbol(payload)
+	if err != nil {
+		return "", err
+	}
+	if e.decodeMap[code[len(code)-1]] > 31 {
+		return "", CorruptInputError(len(code) - 1)
+	}
+	if e.decodeMap[check] != e.decodeMap[code[len(code)-1]] {
+		return "", ErrCheckSymbol
+	}
+
+	return payload, nil
+}
+
+// Grouping
+
+// Group splits code into groups of size symbols joined by sep, to make long
+// codes easier to read and type.
+func Group(code string, size int, sep string) string {
+	if size <= 0 || len(code) <= size {
+		return code
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(code); i += size {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(code[i:min(i+size, len(code))])
+	}
+
+	return b.String()
+}
+
+func Ungroup(code string, sep string) string {
+	if len(sep) == 0 {
+		return code
+	}
+
+	return strings.ReplaceAll(code, sep, "")
+}
@@ -0,0 +1,98 @@
+package zrockford32_test
+
+import (
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func TestCheckSymbolRoundTrip(t *testing.T) {
+	for _, tc := range byteTestsStd {
+		code, err := zrockford32.StdEncoding.AddCheckSymbol(tc.encoded)
+		if err != nil {
+			t.Errorf("AddCheckSymbol %q: error: %v", tc.encoded, err)
+			continue
+		}
+		if g, e := len(code), len(tc.encoded)+1; g != e {
+			t.Errorf("AddCheckSymbol %q wrong length: %d != %d", tc.encoded, g, e)
+		}
+
+		payload, err := zrockford32.StdEncoding.VerifyCheckSymbol(code)
+		if err != nil {
+			t.Errorf("VerifyCheckSymbol %q: error: %v", code, err)
+			continue
+		}
+		if g, e := payload, tc.encoded; g != e {
+			t.Errorf("VerifyCheckSymbol %q wrong result: %q != %q", code, g, e)
+		}
+	}
+}
+
+func TestCheckSymbolDetectsSubstitution(t *testing.T) {
+	const alphabet = "YBNDRFG8EJKMCPQX0T1VW2SZA345H769"
+	code, _ := zrockford32.StdEncoding.AddCheckSymbol("AB3SR12X8FHFNVZAE075FKN3A7XH8VDK6JS22K0")
+
+	for i := 0; i < len(code); i++ {
+		for j := 0; j < len(alphabet); j++ {
+			if alphabet[j] == code[i] {
+				continue
+			}
+			typo := code[:i] + string(alphabet[j]) + code[i+1:]
+			if _, err := zrockford32.StdEncoding.VerifyCheckSymbol(typo); err != zrockford32.ErrCheckSymbol {
+				t.Errorf("VerifyCheckSymbol %q: wrong error: %v", typo, err)
+			}
+		}
+	}
+}
+
+func TestCheckSymbolDetectsTransposition(t *testing.T) {
+	code, _ := zrockford32.StdEncoding.AddCheckSymbol("AB3SR12X8FHFNVZAE075FKN3A7XH8VDK6JS22K0")
+
+	for i := 0; i+1 < len(code); i++ {
+		if code[i] == code[i+1] {
+			continue
+		}
+		typo := code[:i] + string(code[i+1]) + string(code[i]) + code[i+2:]
+		if _, err := zrockford32.StdEncoding.VerifyCheckSymbol(typo); err != zrockford32.ErrCheckSymbol {
+			t.Errorf("VerifyCheckSymbol %q: wrong error: %v", typo, err)
+		}
+	}
+}
+
+func TestCheckSymbolBad(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		offset int64
+	}{
+		{"", 0},
+		{"NY!T", 2},
+		{"NYE!", 3},
+	} {
+		_, err := zrockford32.StdEncoding.VerifyCheckSymbol(tc.input)
+		if err, ok := err.(zrockford32.CorruptInputError); !ok || int64(err) != tc.offset {
+			t.Errorf("VerifyCheckSymbol %q: wrong error: %v", tc.input, err)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	for _, tc := range []struct {
+		code    string
+		size    int
+		grouped string
+	}{
+		{"", 4, ""},
+		{"YBND", 4, "YBND"},
+		{"YBNDR", 4, "YBND-R"},
+		{"YBNDRFG8", 4, "YBND-RFG8"},
+		{"YBNDRFG8", 0, "YBNDRFG8"},
+	} {
+		g := zrockford32.Group(tc.code, tc.size, "-")
+		if g != tc.grouped {
+			t.Errorf("Group %q by %d wrong result: %q != %q", tc.code, tc.size, g, tc.grouped)
+		}
+		if u := zrockford32.Ungroup(g, "-"); u != tc.code {
+			t.Errorf("Ungroup %q wrong result: %q != %q", g, u, tc.code)
+		}
+	}
+}
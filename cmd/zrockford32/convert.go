@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+// Representations
+
+type format string
+
+const (
+	formatRaw    format = "raw"
+	formatHex    format = "hex"
+	formatBase64 format = "base64"
+	formatZ32    format = "z32"
+)
+
+func parseFormat(name string) (format, error) {
+	switch f := format(strings.ToLower(name)); f {
+	case formatRaw, formatHex, formatBase64, formatZ32:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("unknown format %q, expected raw, hex, base64 or z32", name)
+}
+
+func (f format) parse(c *codec, s string) ([]byte, error) {
+	switch f {
+	case formatHex:
+		return hex.DecodeString(s)
+	case formatBase64:
+		return base64.StdEncoding.DecodeString(s)
+	case formatZ32:
+		return c.decode(s)
+	}
+
+	return []byte(s), nil
+}
+
+func (f format) format(c *codec, b []byte) (string, error) {
+	switch f {
+	case formatHex:
+		return hex.EncodeToString(b), nil
+	case formatBase64:
+		return base64.StdEncoding.EncodeToString(b), nil
+	case formatZ32:
+		return c.encode(b)
+	}
+
+	return string(b), nil
+}
+
+// binaryFormatFlags registers -hex and -base64, and returns the format they
+// select, raw by default.
+func binaryFormatFlags(flags *flag.FlagSet, usage string) func() (format, error) {
+	hexFlag := flags.Bool("hex", false, usage+" as hexadecimal")
+	base64Flag := flags.Bool("base64", false, usage+" as base64")
+
+	return func() (format, error) {
+		switch {
+		case *hexFlag && *base64Flag:
+			return "", fmt.Errorf("-hex cannot be combined with -base64")
+		case *hexFlag:
+			return formatHex, nil
+		case *base64Flag:
+			return formatBase64, nil
+		}
+
+		return formatRaw, nil
+	}
+}
+
+// Commands
+
+func runEncode(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("encode", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	common := addIOFlags(flags)
+	encodingFlags := addEncodingFlags(flags, true)
+	lineFlags := addLineFlags(flags)
+	inputFormat := binaryFormatFlags(flags, "Read input")
+	qrFlags := addQRFlags(flags)
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	from, err := inputFormat()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	if qrFlags.enabled() {
+		if *lineFlags.lines {
+			fmt.Fprintln(stderr, "zrockford32: -qr cannot be combined with -lines")
+			return usageError
+		}
+		return encodeQR(common, encodingFlags, qrFlags, from, flags.Args(), stdin, stdout, stderr)
+	}
+
+	return convert(common, encodingFlags, lineFlags, from, formatZ32, flags.Args(), stdin, stdout, stderr)
+}
+
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("decode", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	common := addIOFlags(flags)
+	encodingFlags := addEncodingFlags(flags, true)
+	lineFlags := addLineFlags(flags)
+	outputFormat := binaryFormatFlags(flags, "Write output")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	to, err := outputFormat()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	return convert(common, encodingFlags, lineFlags, formatZ32, to, flags.Args(), stdin, stdout, stderr)
+}
+
+func runConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	common := addIOFlags(flags)
+	encodingFlags := addEncodingFlags(flags, true)
+	lineFlags := addLineFlags(flags)
+	fromFlag := flags.String("from", "z32", "Input representation: raw, hex, base64 or z32")
+	toFlag := flags.String("to", "hex", "Output representation: raw, hex, base64 or z32")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	from, err := parseFormat(*fromFlag)
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+	to, err := parseFormat(*toFlag)
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	return convert(common, encodingFlags, lineFlags, from, to, flags.Args(), stdin, stdout, stderr)
+}
+
+// convert transforms every positional value, or the whole input when there
+// are none, from one representation to another. Values are written one per
+// line, except for raw output which is written as is.
+func convert(common *ioFlags, encodingFlags *encodingFlags, lineFlags *lineFlags, from, to format, values []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(values) > 0 && *common.input != "" {
+		fmt.Fprintln(stderr, "zrockford32: -input cannot be combined with values")
+		return usageError
+	}
+	if len(values) > 0 && *lineFlags.lines {
+		fmt.Fprintln(stderr, "zrockford32: -lines cannot be combined with values")
+		return usageError
+	}
+	if *lineFlags.field != 0 && (!*lineFlags.lines || *lineFlags.field < 0 || *lineFlags.delimiter == "") {
+		fmt.Fprintln(stderr, "zrockford32: -field requires -lines, a positive field number and a delimiter")
+		return usageError
+	}
+
+	c, err := encodingFlags.codec()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	input, output, code := common.open(stdin, stdout, stderr)
+	if code != success {
+		return code
+	}
+
+	if *lineFlags.lines {
+		code, err := convertLines(c, lineFlags, from, to, input, output, stderr)
+		if status := common.finish(input, output, stderr, err); status != success {
+			return status
+		}
+
+		return code
+	}
+
+	if len(values) == 0 {
+		return common.finish(input, output, stderr, convertStream(c, from, to, input, output))
+	}
+
+	for _, value := range values {
+		if err = convertValue(c, from, to, value, output); err != nil {
+			break
+		}
+	}
+
+	return common.finish(input, output, stderr, err)
+}
+
+func convertValue(c *codec, from, to format, value string, output io.Writer) error {
+	b, err := from.parse(c, value)
+	if err != nil {
+		return err
+	}
+
+	s, err := to.format(c, b)
+	if err != nil {
+		return err
+	}
+	if to != formatRaw {
+		s += "\n"
+	}
+
+	_, err = io.WriteString(output, s)
+	return err
+}
+
+func convertStream(c *codec, from, to format, input io.Reader, output io.Writer) error {
+	// Raw bytes are streamed straight through the encoder, unless a bit count
+	// requires the whole input to be known.
+	if from == formatRaw && to == formatZ32 && c.bits < 0 {
+		stream := zrockford32.NewEncoder(c.encoding, output)
+		if _, err := io.Copy(stream, input); err != nil {
+			stream.Close()
+			return err
+		}
+
+		return stream.Close()
+	}
+
+	// Likewise, codes are streamed through the decoder into raw bytes, with
+	// line breaks skipped so that wrapped input decodes as well.
+	if from == formatZ32 && to == formatRaw && c.bits < 0 {
+		stream := zrockford32.NewDecoder(c.decoding.WithIgnoredChars("\r\n"), input)
+		_, err := io.Copy(output, stream)
+		return err
+	}
+
+	b, err := io.ReadAll(input)
+	if err != nil {
+		return err
+	}
+
+	value := string(b)
+	if from != formatRaw {
+		value = strings.TrimRight(value, "\r\n")
+	}
+
+	if to == formatRaw {
+		b, err := from.parse(c, value)
+		if err != nil {
+			return err
+		}
+
+		_, err = output.Write(b)
+		return err
+	}
+
+	return convertValue(c, from, to, value, output)
+}
+
+// Line mode
+
+type lineFlags struct {
+	lines           *bool
+	field           *int
+	delimiter       *string
+	continueOnError *bool
+}
+
+func addLineFlags(flags *flag.FlagSet) *lineFlags {
+	return &lineFlags{
+		lines:           flags.Bool("lines", false, "Transform each line of the input independently"),
+		field:           flags.Int("field", 0, "With -lines, only transform this field of each line, counting from 1"),
+		delimiter:       flags.String("delimiter", ",", "With -field, the delimiter between fields, quotes are not interpreted"),
+		continueOnError: flags.Bool("continue-on-error", false, "With -lines, report invalid lines, copy them unchanged and go on"),
+	}
+}
+
+// convertLines transforms input line by line. Errors are returned as is unless
+// -continue-on-error is set, in which case they are reported and the returned
+// exit code reflects the last of them.
+func convertLines(c *codec, f *lineFlags, from, to format, input io.Reader, output io.Writer, stderr io.Writer) (int, error) {
+	status := success
+	reader := bufio.NewReader(input)
+
+	for n := 1; ; n++ {
+		line, err := reader.ReadString('\n')
+		if len(line) == 0 && err == io.EOF {
+			return status, nil
+		} else if err != nil && err != io.EOF {
+			return status, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		converted, lineErr := convertLine(c, f, from, to, line)
+		if lineErr != nil {
+			lineErr = &lineError{line: n, err: lineErr}
+			if !*f.continueOnError {
+				return status, lineErr
+			}
+
+			var message string
+			message, status = describe(lineErr)
+			fmt.Fprintf(stderr, "zrockford32: %s\n", message)
+			converted = line
+		}
+
+		if _, err := io.WriteString(output, converted+"\n"); err != nil {
+			return status, err
+		}
+	}
+}
+
+func convertLine(c *codec, f *lineFlags, from, to format, line string) (string, error) {
+	if *f.field == 0 {
+		b, err := from.parse(c, line)
+		if err != nil {
+			return "", err
+		}
+
+		return to.format(c, b)
+	}
+
+	fields := strings.Split(line, *f.delimiter)
+	if *f.field > len(fields) {
+		return "", fmt.Errorf("missing field %d", *f.field)
+	}
+
+	b, err := from.parse(c, fields[*f.field-1])
+	if err != nil {
+		return "", err
+	}
+	if fields[*f.field-1], err = to.format(c, b); err != nil {
+		return "", err
+	}
+
+	return strings.Join(fields, *f.delimiter), nil
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+// maxDuplicates is the number of consecutive duplicate codes after which gen
+// gives up, when the space of codes is nearly empty.
+const maxDuplicates = 1000
+
+type generatedCode struct {
+	Code      string    `json:"code"`
+	Entropy   int       `json:"entropy"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func runGen(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	common := addOutputFlags(flags)
+	encodingFlags := addEncodingFlags(flags, false)
+	bitsFlag := flags.Int("bits", 80, "Number of random bits in each code")
+	countFlag := flags.Int("count", 1, "Number of codes to generate, all distinct")
+	groupFlag := flags.Int("group", 0, "Split codes in groups of this many symbols")
+	sepFlag := flags.String("sep", "-", "Separator between groups")
+	checkFlag := flags.Bool("check", false, "Append a check symbol to each code")
+	blocklistFlag := flags.Bool("blocklist", true, "Reject codes spelling offensive words")
+	formatFlag := flags.String("format", "text", "Output format: text, csv or json")
+	seedFlag := flags.String("seed-file", "", "Derive codes from the content of this file instead of crypto/rand, for reproducible fixtures")
+	qrFlags := addQRFlags(flags)
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(stderr, "zrockford32: gen does not take values")
+		return usageError
+	}
+
+	c, err := encodingFlags.codec()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	switch *formatFlag {
+	case "text", "csv", "json":
+	default:
+		fmt.Fprintf(stderr, "zrockford32: unknown format %q, expected text, csv or json\n", *formatFlag)
+		return usageError
+	}
+
+	if *countFlag < 0 || *bitsFlag <= 0 {
+		fmt.Fprintln(stderr, "zrockford32: -bits and -count must be positive")
+		return usageError
+	}
+	if qrFlags.enabled() {
+		if *formatFlag != "text" {
+			fmt.Fprintln(stderr, "zrockford32: -qr cannot be combined with -format")
+			return usageError
+		}
+		if err := qrFlags.check(*countFlag); err != nil {
+			fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+			return usageError
+		}
+	}
+	if *bitsFlag < 63 && uint64(*countFlag) > 1<<uint(*bitsFlag) {
+		fmt.Fprintf(stderr, "zrockford32: cannot generate %d distinct codes of %d bits\n", *countFlag, *bitsFlag)
+		return usageError
+	}
+
+	opts := []zrockford32.GenerateOption{
+		zrockford32.WithEncoding(c.encoding),
+		zrockford32.WithGrouping(*groupFlag, *sepFlag),
+	}
+	if *checkFlag {
+		opts = append(opts, zrockford32.WithCheckSymbol())
+	}
+	if *blocklistFlag {
+		opts = append(opts, zrockford32.WithBlocklist(zrockford32.DefaultBlocklist))
+	}
+	if *seedFlag != "" {
+		seed, err := os.ReadFile(*seedFlag)
+		if err != nil {
+			fmt.Fprintf(stderr, "zrockford32: failed to read seed: %v\n", err)
+			return ioError
+		}
+		opts = append(opts, zrockford32.WithRand(newSeededReader(seed)))
+	}
+
+	codes, err := generate(*countFlag, *bitsFlag, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return generalError
+	}
+
+	input, output, code := common.open(stdin, stdout, stderr)
+	if code != success {
+		return code
+	}
+
+	switch {
+	case qrFlags.enabled():
+		for _, code := range codes {
+			if err = qrFlags.write(output, code.Code); err != nil {
+				break
+			}
+		}
+	case *formatFlag == "csv":
+		err = writeCSV(output, codes)
+	case *formatFlag == "json":
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(codes)
+	default:
+		for _, code := range codes {
+			if _, err = fmt.Fprintln(output, code.Code); err != nil {
+				break
+			}
+		}
+	}
+
+	return common.finish(input, output, stderr, err)
+}
+
+func generate(count, bits int, opts []zrockford32.GenerateOption) ([]generatedCode, error) {
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	codes := make([]generatedCode, 0, count)
+	seen := make(map[string]bool, count)
+
+	for duplicates := 0; len(codes) < count; {
+		code, err := zrockford32.GenerateCode(bits, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[code.Code] {
+			if duplicates++; float64(duplicates) > duplicateLimit(bits, len(codes)) {
+				return nil, errors.New("too many duplicate codes, use more bits")
+			}
+			continue
+		}
+		seen[code.Code] = true
+		duplicates = 0
+
+		codes = append(codes, generatedCode{Code: code.Code, Entropy: code.Entropy, CreatedAt: createdAt})
+	}
+
+	return codes, nil
+}
+
+// duplicateLimit returns the number of consecutive duplicates tolerated once n
+// codes of bits bits are generated. Duplicates become likelier as the codes
+// fill the space, so the limit grows with the expected number of attempts
+// needed to find a new code.
+func duplicateLimit(bits, n int) float64 {
+	if bits >= 63 {
+		return maxDuplicates
+	}
+
+	space := float64(uint64(1) << uint(bits))
+	return maxDuplicates * space / (space - float64(n))
+}
+
+func writeCSV(output io.Writer, codes []generatedCode) error {
+	writer := csv.NewWriter(output)
+	writer.Write([]string{"code", "entropy", "created_at"})
+
+	for _, code := range codes {
+		writer.Write([]string{code.Code, strconv.Itoa(code.Entropy), code.CreatedAt.Format(time.RFC3339)})
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// seededReader is a deterministic stream of bytes derived from a seed with
+// SHA-256 in counter mode.
+type seededReader struct {
+	seed    [sha256.Size]byte
+	counter uint64
+	block   []byte
+}
+
+func newSeededReader(seed []byte) *seededReader {
+	return &seededReader{seed: sha256.Sum256(seed)}
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+
+	for n < len(p) {
+		if len(r.block) == 0 {
+			var input [sha256.Size + 8]byte
+			copy(input[:], r.seed[:])
+			binary.BigEndian.PutUint64(input[sha256.Size:], r.counter)
+			r.counter++
+
+			block := sha256.Sum256(input[:])
+			r.block = block[:]
+		}
+
+		m := copy(p[n:], r.block)
+		r.block = r.block[m:]
+		n += m
+	}
+
+	return n, nil
+}
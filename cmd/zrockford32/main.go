@@ -1,104 +1,413 @@
 package main
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/checksum0/go-zrockford32"
+	"github.com/checksum0/go-zrockford32/seal"
+	"github.com/checksum0/go-zrockford32/shamir"
 )
 
-const generalError = 1
+const (
+	success = iota
+	generalError
+	usageError
+	ioError
+	corruptInputError
+)
+
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"encode":  runEncode,
+	"decode":  runDecode,
+	"convert": runConvert,
+	"gen":     runGen,
+	"verify":  runVerify,
+	"seal":    runSeal,
+	"open":    runOpen,
+	"split":   runSplit,
+	"combine": runCombine,
+}
+
+const usage = `Usage: zrockford32 <command> [flags] [value...]
+
+Commands:
+  encode   encode values or input to zrockford32, as text or QR codes
+  decode   decode zrockford32 values or input
+  convert  convert values between representations
+  gen      generate random codes
+  verify   check whether codes are valid
+  seal     protect a secret with a passphrase as a printable code
+  open     recover a secret sealed with seal
+  split    split a secret into shares, some of which recover it
+  combine  recover a secret from its shares
+
+Run zrockford32 <command> -h for the flags of a command.
+`
 
 func main() {
-	inputFlag := flag.String("input", "", "Input file to read from, defaults to stdin")
-	outputFlag := flag.String("output", "", "Output file to write to, defaults to stdout")
-	decodeFlag := flag.Bool("decode", false, "Decode input instead of encoding")
-	lowercaseFlag := flag.Bool("lowercase", false, "Use lowercase encoding instead of uppercase")
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
 
-	flag.Parse()
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "-help" || args[0] == "help") {
+		fmt.Fprint(stderr, usage)
+		return success
+	}
 
-	input, err := getInput(*inputFlag)
-	defer input.Close()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to open %s for input.\n", *inputFlag)
-		os.Exit(generalError)
+	// Without a command, behave as encode so that plain stream usage such
+	// as "zrockford32 -input file" keeps working.
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runEncode(args, stdin, stdout, stderr)
 	}
 
-	output, err := getOutput(*outputFlag)
-	defer output.Close()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to open %s for output.\n", *outputFlag)
-		os.Exit(generalError)
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "zrockford32: unknown command %q\n", args[0])
+		fmt.Fprint(stderr, usage)
+		return usageError
+	}
+
+	return cmd(args[1:], stdin, stdout, stderr)
+}
+
+func parseFlags(flags *flag.FlagSet, args []string) (int, bool) {
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return success, false
+		}
+		return usageError, false
+	}
+
+	return success, true
+}
+
+func report(stderr io.Writer, err error) int {
+	message, code := describe(err)
+	fmt.Fprintf(stderr, "zrockford32: %s\n", message)
+
+	return code
+}
+
+// describe returns a diagnostic for err, and the matching exit code.
+func describe(err error) (string, int) {
+	var line *lineError
+	var corrupt zrockford32.CorruptInputError
+	var corruptBase64 base64.CorruptInputError
+	var invalidHex hex.InvalidByteError
+
+	switch {
+	case errors.As(err, &line):
+		// Errors on a line come from its content rather than from I/O.
+		message, code := describe(line.err)
+		if code == ioError {
+			code = corruptInputError
+		}
+		return fmt.Sprintf("line %d: %s", line.line, message), code
+	case errors.As(err, &corrupt):
+		return fmt.Sprintf("corrupt input at byte %d", int64(corrupt)), corruptInputError
+	case errors.As(err, &corruptBase64):
+		return fmt.Sprintf("corrupt base64 input at byte %d", int64(corruptBase64)), corruptInputError
+	case errors.As(err, &invalidHex), errors.Is(err, hex.ErrLength):
+		return fmt.Sprintf("corrupt hex input: %v", err), corruptInputError
+	case errors.Is(err, zrockford32.ErrChecksum), errors.Is(err, zrockford32.ErrPrefix),
+		errors.Is(err, seal.ErrOpen), errors.Is(err, seal.ErrVersion),
+		errors.Is(err, shamir.ErrVersion), errors.Is(err, shamir.ErrMismatch),
+		errors.Is(err, shamir.ErrTooFew), errors.Is(err, shamir.ErrInvalid):
+		return err.Error(), corruptInputError
+	}
+
+	return err.Error(), ioError
+}
+
+type lineError struct {
+	line int
+	err  error
+}
+
+func (e *lineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.line, e.err)
+}
+
+func (e *lineError) Unwrap() error {
+	return e.err
+}
+
+// Encodings
+
+type encodingFlags struct {
+	alphabet    *string
+	lowercase   *bool
+	ignoreCase  *bool
+	ignoreChars *string
+	bits        *int
+}
+
+// addEncodingFlags registers the flags selecting the encoding, with -bits only
+// when withBits is set.
+func addEncodingFlags(flags *flag.FlagSet, withBits bool) *encodingFlags {
+	f := &encodingFlags{
+		alphabet:    flags.String("alphabet", "", "Encoding name ("+strings.Join(zrockford32.Encodings(), ", ")+") or custom 32 character alphabet"),
+		lowercase:   flags.Bool("lowercase", false, "Use lowercase encoding instead of uppercase"),
+		ignoreCase:  flags.Bool("ignore-case", false, "Accept symbols of either case when decoding"),
+		ignoreChars: flags.String("ignore-chars", "", "Characters to skip when decoding, such as group separators"),
 	}
+	if withBits {
+		f.bits = flags.Int("bits", -1, "Encode or decode exactly this number of bits")
+	}
+
+	return f
+}
 
-	if *decodeFlag {
-		if *lowercaseFlag {
-			err = decodeLwr(input, output)
-		} else {
-			err = decodeStd(input, output)
+// codec holds the encoding selected by the flags, and its tolerant variant used
+// for decoding.
+type codec struct {
+	encoding    *zrockford32.Encoding
+	decoding    *zrockford32.Encoding
+	bits        int
+	ignoreChars string
+}
+
+func (f *encodingFlags) codec() (*codec, error) {
+	encoding := zrockford32.StdEncoding
+	switch {
+	case *f.alphabet != "" && *f.lowercase:
+		return nil, errors.New("-alphabet cannot be combined with -lowercase")
+	case *f.alphabet != "":
+		var err error
+		if encoding, err = lookupAlphabet(*f.alphabet); err != nil {
+			return nil, err
 		}
-	} else {
-		if *lowercaseFlag {
-			err = encodeLwr(input, output)
-		} else {
-			err = encodeStd(input, output)
+	case *f.lowercase:
+		encoding = zrockford32.LwrEncoding
+	}
+
+	c := &codec{encoding: encoding, decoding: encoding, bits: -1}
+	if f.bits != nil {
+		c.bits = *f.bits
+	}
+
+	if *f.ignoreCase {
+		c.decoding = c.decoding.WithIgnoreCase()
+	}
+	if *f.ignoreChars != "" {
+		if strings.ContainsAny(encoding.Alphabet(), *f.ignoreChars) {
+			return nil, errors.New("-ignore-chars overlaps the alphabet")
 		}
+		c.decoding = c.decoding.WithIgnoredChars(*f.ignoreChars)
+		c.ignoreChars = *f.ignoreChars
+	}
+
+	return c, nil
+}
+
+func lookupAlphabet(name string) (*zrockford32.Encoding, error) {
+	if encoding, ok := zrockford32.LookupEncoding(name); ok {
+		return encoding, nil
+	}
+
+	if err := zrockford32.ValidateAlphabet(name); err != nil {
+		return nil, fmt.Errorf("%q is neither a known encoding nor a valid alphabet: %v", name, err)
 	}
+
+	return zrockford32.NewEncoding(name), nil
+}
+
+func (c *codec) encode(b []byte) (string, error) {
+	if c.bits < 0 {
+		return c.encoding.EncodeToString(b), nil
+	}
+
+	if len(b)*8 < c.bits {
+		return "", fmt.Errorf("cannot encode %d bits from %d bytes", c.bits, len(b))
+	}
+
+	return c.encoding.EncodeBitsToString(b, c.bits), nil
+}
+
+func (c *codec) decode(s string) ([]byte, error) {
+	if c.bits < 0 {
+		return c.decoding.DecodeString(s)
+	}
+
+	return c.decoding.DecodeBitsString(s, c.bits)
+}
+
+// Input and output
+
+type ioFlags struct {
+	input  *string
+	output *string
+	force  *bool
+	append *bool
+	atomic *bool
+}
+
+func addIOFlags(flags *flag.FlagSet) *ioFlags {
+	f := addOutputFlags(flags)
+	f.input = flags.String("input", "", "Input file to read from, defaults to stdin")
+
+	return f
+}
+
+// addOutputFlags registers the output flags only, for commands which do not
+// read any input.
+func addOutputFlags(flags *flag.FlagSet) *ioFlags {
+	return &ioFlags{
+		input:  new(string),
+		output: flags.String("output", "", "Output file to write to, defaults to stdout"),
+		force:  flags.Bool("force", false, "Overwrite the output file if it already exists"),
+		append: flags.Bool("append", false, "Append to the output file if it already exists"),
+		atomic: flags.Bool("atomic", false, "Write the output file to a temporary file and rename it once complete"),
+	}
+}
+
+func (f *ioFlags) open(stdin io.Reader, stdout, stderr io.Writer) (io.ReadCloser, *output, int) {
+	if *f.append && (*f.force || *f.atomic) {
+		fmt.Fprintln(stderr, "zrockford32: -append cannot be combined with -force or -atomic")
+		return nil, nil, usageError
+	}
+
+	input, err := getInput(*f.input, stdin)
 	if err != nil {
-		os.Exit(generalError)
+		fmt.Fprintf(stderr, "zrockford32: failed to open %s for input: %v\n", *f.input, err)
+		return nil, nil, ioError
 	}
 
-	return
+	output, err := getOutput(*f.output, stdout, *f.force, *f.append, *f.atomic)
+	if errors.Is(err, os.ErrExist) {
+		input.Close()
+		fmt.Fprintf(stderr, "zrockford32: %s already exists, use -force to overwrite or -append to append to it\n", *f.output)
+		return nil, nil, ioError
+	} else if err != nil {
+		input.Close()
+		fmt.Fprintf(stderr, "zrockford32: failed to open %s for output: %v\n", *f.output, err)
+		return nil, nil, ioError
+	}
+
+	return input, output, success
 }
 
-func getInput(path string) (*os.File, error) {
+// finish commits the output unless err is set, and reports the outcome.
+func (f *ioFlags) finish(input io.Closer, output *output, stderr io.Writer, err error) int {
+	input.Close()
+
+	if err != nil {
+		output.Abort()
+		return report(stderr, err)
+	}
+
+	if err := output.Commit(); err != nil {
+		fmt.Fprintf(stderr, "zrockford32: failed to write %s: %v\n", *f.output, err)
+		return ioError
+	}
+
+	return success
+}
+
+func getInput(path string, stdin io.Reader) (io.ReadCloser, error) {
 	if len(path) == 0 || path == "-" {
-		return os.Stdin, nil
+		return io.NopCloser(stdin), nil
 	}
 
 	return os.Open(path)
 }
 
-func getOutput(path string) (*os.File, error) {
+type output struct {
+	io.Writer
+	file *os.File
+	path string
+	temp string
+	mode os.FileMode
+	// created is set when this run created path, which Abort then removes.
+	created bool
+}
+
+func getOutput(path string, stdout io.Writer, force, appendMode, atomic bool) (*output, error) {
 	if len(path) == 0 || path == "-" {
-		return os.Stdout, nil
+		return &output{Writer: stdout}, nil
 	}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return os.Create(path)
+	if atomic {
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(path); err == nil {
+			if !force {
+				return nil, os.ErrExist
+			}
+			mode = info.Mode().Perm()
+		}
+
+		file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+		if err != nil {
+			return nil, err
+		}
+
+		return &output{Writer: file, file: file, path: path, temp: file.Name(), mode: mode}, nil
 	}
 
-	return os.Open(path)
-}
+	flags := os.O_WRONLY | os.O_CREATE
+	switch {
+	case appendMode:
+		flags |= os.O_APPEND
+	case force:
+		flags |= os.O_TRUNC
+	default:
+		flags |= os.O_EXCL
+	}
 
-func decodeStd(input io.Reader, output io.Writer) error {
-	stream := zrockford32.NewDecoder(zrockford32.StdEncoding, input)
-	_, err := io.Copy(output, stream)
+	_, statErr := os.Stat(path)
+	file, err := os.OpenFile(path, flags, 0666)
+	if err != nil {
+		return nil, err
+	}
 
-	return err
+	return &output{Writer: file, file: file, path: path, created: os.IsNotExist(statErr)}, nil
 }
 
-func decodeLwr(input io.Reader, output io.Writer) error {
-	stream := zrockford32.NewDecoder(zrockford32.LwrEncoding, input)
-	_, err := io.Copy(output, stream)
+func (o *output) Commit() error {
+	if o.file == nil {
+		return nil
+	}
 
-	return err
-}
+	if o.temp == "" {
+		return o.file.Close()
+	}
 
-func encodeStd(input io.Reader, output io.Writer) error {
-	stream := zrockford32.NewEncoder(zrockford32.StdEncoding, output)
-	defer stream.Close()
-	_, err := io.Copy(stream, input)
+	if err := o.file.Sync(); err != nil {
+		o.Abort()
+		return err
+	}
+	if err := o.file.Close(); err != nil {
+		os.Remove(o.temp)
+		return err
+	}
+	if err := os.Chmod(o.temp, o.mode); err != nil {
+		os.Remove(o.temp)
+		return err
+	}
 
-	return err
+	return os.Rename(o.temp, o.path)
 }
 
-func encodeLwr(input io.Reader, output io.Writer) error {
-	stream := zrockford32.NewEncoder(zrockford32.LwrEncoding, output)
-	defer stream.Close()
-	_, err := io.Copy(stream, input)
+func (o *output) Abort() {
+	if o.file == nil {
+		return
+	}
 
-	return err
+	o.file.Close()
+	switch {
+	case o.temp != "":
+		os.Remove(o.temp)
+	case o.created:
+		os.Remove(o.path)
+	}
 }
@@ -0,0 +1,807 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func runCommand(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+
+	return string(b)
+}
+
+func TestEncodeStdin(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "hello, world\n")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := stdout, "PB1SA5DXF008Q551PT1YW"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+}
+
+func TestDecodeStdin(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "pb1sa5dxf008q551pt1yw\n", "decode", "-lowercase")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := stdout, "hello, world\n"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	data := bytes.Repeat([]byte("hello, world\n"), 1000)
+	encoded := zrockford32.StdEncoding.EncodeToString(data)
+
+	// Wrap the code in lines of 76 symbols, as produced by fold.
+	var input strings.Builder
+	for line := encoded; len(line) > 0; {
+		n := 76
+		if len(line) < n {
+			n = len(line)
+		}
+		input.WriteString(line[:n] + "\r\n")
+		line = line[n:]
+	}
+
+	code, stdout, stderr := runCommand(t, input.String(), "decode")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if stdout != string(data) {
+		t.Errorf("wrong output of %d bytes, expected %d", len(stdout), len(data))
+	}
+
+	code, _, stderr = runCommand(t, encoded[:800]+"!"+encoded[800:], "decode")
+	if code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := stderr, "zrockford32: corrupt input at byte 800\n"; g != e {
+		t.Errorf("wrong diagnostic: %q != %q", g, e)
+	}
+}
+
+func TestInputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input")
+	os.WriteFile(path, []byte{0x34, 0x5a}, 0644)
+
+	code, stdout, stderr := runCommand(t, "", "-input", path)
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := stdout, "GTPY"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+}
+
+func TestMissingInputFile(t *testing.T) {
+	code, _, stderr := runCommand(t, "", "-input", filepath.Join(t.TempDir(), "missing"))
+	if code != ioError {
+		t.Errorf("wrong exit code: %d != %d", code, ioError)
+	}
+	if !strings.Contains(stderr, "failed to open") {
+		t.Errorf("wrong diagnostic: %q", stderr)
+	}
+}
+
+func TestCorruptInput(t *testing.T) {
+	code, _, stderr := runCommand(t, "F00!BAR", "decode")
+	if code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := stderr, "zrockford32: corrupt input at byte 3\n"; g != e {
+		t.Errorf("wrong diagnostic: %q != %q", g, e)
+	}
+}
+
+func TestOutputNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+
+	code, stdout, stderr := runCommand(t, "\x34\x5a", "-output", path)
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("unexpected stdout: %q", stdout)
+	}
+	if g, e := readFile(t, path), "GTPY"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+}
+
+func TestOutputExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	os.WriteFile(path, []byte("previous"), 0644)
+
+	for _, args := range [][]string{
+		{"-output", path},
+		{"-output", path, "-atomic"},
+	} {
+		code, _, stderr := runCommand(t, "\x34\x5a", args...)
+		if code != ioError {
+			t.Errorf("%v: wrong exit code: %d != %d", args, code, ioError)
+		}
+		if !strings.Contains(stderr, "already exists") {
+			t.Errorf("%v: wrong diagnostic: %q", args, stderr)
+		}
+		if g, e := readFile(t, path), "previous"; g != e {
+			t.Errorf("%v: output modified: %q != %q", args, g, e)
+		}
+	}
+}
+
+func TestOutputForce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	os.WriteFile(path, []byte("previous content"), 0644)
+
+	code, _, stderr := runCommand(t, "\x34\x5a", "-output", path, "-force")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := readFile(t, path), "GTPY"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+}
+
+func TestOutputAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	os.WriteFile(path, []byte("9H\n"), 0644)
+
+	code, _, stderr := runCommand(t, "\x34\x5a", "-output", path, "-append")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := readFile(t, path), "9H\nGTPY"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+}
+
+func TestOutputAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "output")
+	os.WriteFile(path, []byte("previous content"), 0600)
+
+	code, _, stderr := runCommand(t, "\x34\x5a", "-output", path, "-atomic", "-force")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := readFile(t, path), "GTPY"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("mode not preserved: %v, %v", info.Mode(), err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestOutputCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "output")
+
+	code, _, _ := runCommand(t, "PB1SA5DX!!", "decode", "-output", path)
+	if code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("output left behind: %v", err)
+	}
+
+	// The output can be written again once the input is fixed.
+	code, _, stderr := runCommand(t, "PB1SA5DX", "decode", "-output", path)
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := readFile(t, path), "hello"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+
+	// Files which existed before are kept.
+	os.WriteFile(path, []byte("previous"), 0644)
+	if code, _, _ := runCommand(t, "PB1SA5DX!!", "decode", "-output", path, "-append"); code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := readFile(t, path), "previous"; g != e {
+		t.Errorf("output modified: %q != %q", g, e)
+	}
+}
+
+func TestOutputAtomicCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "output")
+	os.WriteFile(path, []byte("previous"), 0644)
+
+	code, _, _ := runCommand(t, "F00!BAR", "decode", "-output", path, "-atomic", "-force")
+	if code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := readFile(t, path), "previous"; g != e {
+		t.Errorf("output modified: %q != %q", g, e)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-unknown"},
+		{"-output", "x", "-append", "-force"},
+		{"-output", "x", "-append", "-atomic"},
+		{"frobnicate"},
+		{"encode", "-hex", "-base64"},
+		{"encode", "-input", "x", "value"},
+		{"convert", "-from", "base58"},
+	} {
+		if code, _, _ := runCommand(t, "", args...); code != usageError {
+			t.Errorf("%v: wrong exit code: %d != %d", args, code, usageError)
+		}
+	}
+}
+
+func TestEncodeArgs(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "", "encode", "hello", "world")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := stdout, "PB1SA5DX\nQ7ZZR5DR\n"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+}
+
+func TestDecodeArgs(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "", "decode", "PB1SA5DXF008Q551PT1YW")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := stdout, "hello, world\n"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+}
+
+func TestBinaryFormats(t *testing.T) {
+	for _, tc := range []struct {
+		args   []string
+		stdin  string
+		stdout string
+	}{
+		{[]string{"encode", "-hex", "345a"}, "", "GTPY\n"},
+		{[]string{"encode", "-base64", "NFo="}, "", "GTPY\n"},
+		{[]string{"encode", "-hex"}, "345a\n", "GTPY\n"},
+		{[]string{"decode", "-hex", "GTPY"}, "", "345a\n"},
+		{[]string{"decode", "-base64"}, "GTPY\n", "NFo=\n"},
+		{[]string{"decode", "-lowercase", "-hex", "gtpy"}, "", "345a\n"},
+		{[]string{"convert", "GTPY"}, "", "345a\n"},
+		{[]string{"convert", "-from", "hex", "-to", "base64", "345a", "ff"}, "", "NFo=\n/w==\n"},
+		{[]string{"convert", "-from", "base64", "-to", "z32"}, "NFo=", "GTPY\n"},
+		{[]string{"convert", "-from", "hex", "-to", "raw", "68690a"}, "", "hi\n"},
+	} {
+		code, stdout, stderr := runCommand(t, tc.stdin, tc.args...)
+		if code != success {
+			t.Errorf("%v: exit code %d, stderr %q", tc.args, code, stderr)
+			continue
+		}
+		if g, e := stdout, tc.stdout; g != e {
+			t.Errorf("%v: wrong output: %q != %q", tc.args, g, e)
+		}
+	}
+}
+
+func TestCorruptBinaryFormats(t *testing.T) {
+	for _, args := range [][]string{
+		{"encode", "-hex", "34z"},
+		{"encode", "-base64", "N!o="},
+		{"decode", "GT!Y"},
+	} {
+		if code, _, _ := runCommand(t, "", args...); code != corruptInputError {
+			t.Errorf("%v: wrong exit code: %d != %d", args, code, corruptInputError)
+		}
+	}
+}
+
+func TestGen(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "", "gen", "-bits", "40", "-count", "3")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("wrong number of codes: %q", stdout)
+	}
+	for _, line := range lines {
+		if len(line) != 8 {
+			t.Errorf("wrong code length: %q", line)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	code, stdout, _ := runCommand(t, "GTPY\n\nGT!Y\n", "verify")
+	if code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := stdout, "GTPY\tvalid\tcanonical\t16 bits\t2 bytes\nGT!Y\tinvalid\tillegal zrockford32 data at input byte 2\n"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+
+	if code, _, _ := runCommand(t, "", "verify", "GTPY"); code != success {
+		t.Errorf("wrong exit code: %d != %d", code, success)
+	}
+}
+
+func TestEncodingFlags(t *testing.T) {
+	for _, tc := range []struct {
+		args   []string
+		stdout string
+	}{
+		{[]string{"encode", "-alphabet", "lwr", "hello"}, "pb1sa5dx\n"},
+		{[]string{"encode", "-alphabet", "ybndrfg8ejkmcpqxot1uwisza345h769", "-hex", "ff"}, "9h\n"},
+		{[]string{"encode", "-bits", "20", "-hex", "101110"}, "NYET\n"},
+		{[]string{"decode", "-bits", "20", "-hex", "NYET"}, "101110\n"},
+		{[]string{"decode", "-hex", "-ignore-case", "gTpY"}, "345a\n"},
+		{[]string{"decode", "-hex", "-ignore-chars", "-", "GT-PY"}, "345a\n"},
+		{[]string{"verify", "-lowercase", "gtpy"}, "gtpy\tvalid\tcanonical\t16 bits\t2 bytes\n"},
+	} {
+		code, stdout, stderr := runCommand(t, "", tc.args...)
+		if code != success {
+			t.Errorf("%v: exit code %d, stderr %q", tc.args, code, stderr)
+			continue
+		}
+		if g, e := stdout, tc.stdout; g != e {
+			t.Errorf("%v: wrong output: %q != %q", tc.args, g, e)
+		}
+	}
+}
+
+func TestEncodingFlagsErrors(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"encode", "-alphabet", "base58", "x"}, usageError},
+		{[]string{"encode", "-alphabet", "lwr", "-lowercase", "x"}, usageError},
+		{[]string{"decode", "-ignore-chars", "Y", "GTPY"}, usageError},
+		{[]string{"encode", "-bits", "20", "-hex", "1011"}, ioError},
+		{[]string{"decode", "gTpY"}, corruptInputError},
+		{[]string{"decode", "GT-PY"}, corruptInputError},
+	} {
+		if code, _, stderr := runCommand(t, "", tc.args...); code != tc.code {
+			t.Errorf("%v: wrong exit code: %d != %d, stderr %q", tc.args, code, tc.code, stderr)
+		}
+	}
+}
+
+func TestGenOptions(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "", "gen", "-bits", "80", "-count", "1000", "-group", "4", "-sep", "-", "-check")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 1000 {
+		t.Fatalf("wrong number of codes: %d", len(lines))
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		if len(line) != 21 || line[4] != '-' {
+			t.Fatalf("wrong code format: %q", line)
+		}
+		if seen[line] {
+			t.Fatalf("duplicate code: %q", line)
+		}
+		seen[line] = true
+
+		payload, err := zrockford32.StdEncoding.VerifyCheckSymbol(zrockford32.Ungroup(line, "-"))
+		if err != nil {
+			t.Fatalf("invalid check symbol in %q: %v", line, err)
+		}
+		if zrockford32.DefaultBlocklist.Match(payload) {
+			t.Fatalf("blocked code generated: %q", line)
+		}
+	}
+}
+
+func TestGenSeed(t *testing.T) {
+	seed := filepath.Join(t.TempDir(), "seed")
+	os.WriteFile(seed, []byte("fixtures"), 0644)
+
+	_, first, _ := runCommand(t, "", "gen", "-count", "5", "-seed-file", seed)
+	code, second, stderr := runCommand(t, "", "gen", "-count", "5", "-seed-file", seed)
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if first != second {
+		t.Errorf("seeded output differs: %q != %q", first, second)
+	}
+
+	_, random, _ := runCommand(t, "", "gen", "-count", "5")
+	if random == first {
+		t.Errorf("unseeded output matches seeded output")
+	}
+}
+
+func TestGenUnique(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "", "gen", "-bits", "4", "-count", "16", "-blocklist=false")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if lines := strings.Fields(stdout); len(lines) != 16 {
+		t.Errorf("wrong number of codes: %q", stdout)
+	}
+
+	if code, _, _ := runCommand(t, "", "gen", "-bits", "4", "-count", "17"); code != usageError {
+		t.Errorf("wrong exit code: %d != %d", code, usageError)
+	}
+
+	// Thousands of collisions are expected, but few in a row.
+	code, stdout, stderr = runCommand(t, "", "gen", "-bits", "20", "-count", "100000", "-blocklist=false")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if lines := strings.Fields(stdout); len(lines) != 100000 {
+		t.Errorf("wrong number of codes: %d", len(lines))
+	}
+}
+
+func TestGenFormats(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "", "gen", "-bits", "40", "-count", "3", "-format", "json")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+
+	var codes []struct {
+		Code      string    `json:"code"`
+		Entropy   int       `json:"entropy"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &codes); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(codes) != 3 {
+		t.Fatalf("wrong number of codes: %d", len(codes))
+	}
+	for _, c := range codes {
+		if len(c.Code) != 8 || c.Entropy != 40 || c.CreatedAt.IsZero() {
+			t.Errorf("wrong code: %+v", c)
+		}
+	}
+
+	code, stdout, stderr = runCommand(t, "", "gen", "-bits", "40", "-count", "3", "-format", "csv")
+	if code != success {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 4 || records[0][0] != "code" || records[1][1] != "40" {
+		t.Errorf("wrong CSV: %q", records)
+	}
+
+	if code, _, _ := runCommand(t, "", "gen", "-format", "xml"); code != usageError {
+		t.Errorf("wrong exit code: %d != %d", code, usageError)
+	}
+}
+
+func TestLines(t *testing.T) {
+	for _, tc := range []struct {
+		args   []string
+		stdin  string
+		stdout string
+	}{
+		{[]string{"encode", "-lines"}, "hello\nworld\n", "PB1SA5DX\nQ7ZZR5DR\n"},
+		{[]string{"encode", "-lines"}, "hello\r\n\nworld", "PB1SA5DX\n\nQ7ZZR5DR\n"},
+		{[]string{"decode", "-lines"}, "PB1SA5DX\nQ7ZZR5DR\n", "hello\nworld\n"},
+		{[]string{"decode", "-lines", "-hex"}, "GTPY\n9H\n", "345a\nff\n"},
+		{[]string{"encode", "-lines", "-field", "2"}, "1,hello,x\n2,world,y\n", "1,PB1SA5DX,x\n2,Q7ZZR5DR,y\n"},
+		{[]string{"decode", "-lines", "-field", "1", "-delimiter", "\t"}, "PB1SA5DX\ta\n", "hello\ta\n"},
+		{[]string{"convert", "-lines", "-from", "hex", "-to", "z32"}, "345a\nff\n", "GTPY\n9H\n"},
+	} {
+		code, stdout, stderr := runCommand(t, tc.stdin, tc.args...)
+		if code != success {
+			t.Errorf("%v: exit code %d, stderr %q", tc.args, code, stderr)
+			continue
+		}
+		if g, e := stdout, tc.stdout; g != e {
+			t.Errorf("%v: wrong output: %q != %q", tc.args, g, e)
+		}
+	}
+}
+
+func TestLinesErrors(t *testing.T) {
+	stdin := "GTPY\nGT!Y\n9H\nx\n"
+
+	code, stdout, stderr := runCommand(t, stdin, "decode", "-lines", "-hex")
+	if code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := stderr, "zrockford32: line 2: corrupt input at byte 2\n"; g != e {
+		t.Errorf("wrong diagnostic: %q != %q", g, e)
+	}
+	if g, e := stdout, "345a\n"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+
+	code, stdout, stderr = runCommand(t, stdin, "decode", "-lines", "-hex", "-continue-on-error")
+	if code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := stderr, "zrockford32: line 2: corrupt input at byte 2\nzrockford32: line 4: corrupt input at byte 0\n"; g != e {
+		t.Errorf("wrong diagnostic: %q != %q", g, e)
+	}
+	if g, e := stdout, "345a\nGT!Y\nff\nx\n"; g != e {
+		t.Errorf("wrong output: %q != %q", g, e)
+	}
+
+	code, _, stderr = runCommand(t, "a,b\na\n", "encode", "-lines", "-field", "2")
+	if code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := stderr, "zrockford32: line 2: missing field 2\n"; g != e {
+		t.Errorf("wrong diagnostic: %q != %q", g, e)
+	}
+
+	for _, args := range [][]string{
+		{"encode", "-lines", "value"},
+		{"encode", "-field", "2"},
+		{"encode", "-lines", "-field", "-1"},
+	} {
+		if code, _, _ := runCommand(t, "", args...); code != usageError {
+			t.Errorf("%v: wrong exit code: %d != %d", args, code, usageError)
+		}
+	}
+}
+
+func TestVerifyReport(t *testing.T) {
+	check, _ := zrockford32.StdEncoding.AddCheckSymbol("GTPY")
+	typo := "GTPB" + check[4:]
+
+	for _, tc := range []struct {
+		args   []string
+		code   int
+		stdout string
+	}{
+		{[]string{"verify", "GTPB"}, success, "GTPB\tvalid\tnon-canonical\t16 bits\t2 bytes\n"},
+		{[]string{"verify", "-bits", "20", "NYET"}, success, "NYET\tvalid\tcanonical\t20 bits\t3 bytes\n"},
+		{[]string{"verify", "-check", check}, success, check + "\tvalid\tcanonical\t16 bits\t2 bytes\n"},
+		{[]string{"verify", "-check", typo}, corruptInputError, typo + "\tinvalid\tcheck symbol mismatch\tdid you mean " + strings.Join(zrockford32.StdEncoding.Suggest(typo, zrockford32.SuggestOptions{CheckSymbol: true, Max: 5}), ", ") + "\n"},
+		{[]string{"verify", "gtpy"}, corruptInputError, "gtpy\tinvalid\tillegal zrockford32 data at input byte 0\n"},
+		{[]string{"verify", "-bits", "16", "gtpy"}, corruptInputError, "gtpy\tinvalid\tillegal zrockford32 data at input byte 0\tdid you mean GTPY\n"},
+		{[]string{"verify", "PB!SA5DX"}, corruptInputError, "PB!SA5DX\tinvalid\tillegal zrockford32 data at input byte 2\n"},
+		{[]string{"verify", "-ignore-chars", "-", "6N-9HQ"}, success, "6N-9HQ\tvalid\tcanonical\t24 bits\t3 bytes\n"},
+		{[]string{"verify", "-ignore-chars", "-", "-bits", "24", "6N-9HO"}, corruptInputError, "6N-9HO\tinvalid\tillegal zrockford32 data at input byte 5\tdid you mean 6N9H0\n"},
+	} {
+		code, stdout, stderr := runCommand(t, "", tc.args...)
+		if code != tc.code {
+			t.Errorf("%v: wrong exit code: %d != %d, stderr %q", tc.args, code, tc.code, stderr)
+		}
+		if g, e := stdout, tc.stdout; g != e {
+			t.Errorf("%v: wrong output: %q != %q", tc.args, g, e)
+		}
+	}
+}
+
+func TestVerifyJSON(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "GTPY\ngtpy\n", "verify", "-json", "-bits", "16")
+	if code != corruptInputError {
+		t.Errorf("wrong exit code: %d != %d, stderr %q", code, corruptInputError, stderr)
+	}
+
+	var reports []map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(stdout))
+	for decoder.More() {
+		var report map[string]interface{}
+		if err := decoder.Decode(&report); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if len(reports) != 2 {
+		t.Fatalf("wrong number of reports: %d", len(reports))
+	}
+	if reports[0]["valid"] != true || reports[0]["canonical"] != true || reports[0]["bytes"] != 2.0 {
+		t.Errorf("wrong report: %v", reports[0])
+	}
+	if _, ok := reports[0]["check"]; ok {
+		t.Errorf("check reported without -check: %v", reports[0])
+	}
+	if reports[1]["valid"] != false || reports[1]["error"] == nil {
+		t.Errorf("wrong report: %v", reports[1])
+	}
+	if suggestions, _ := reports[1]["suggestions"].([]interface{}); len(suggestions) != 1 || suggestions[0] != "GTPY" {
+		t.Errorf("wrong suggestions: %v", reports[1]["suggestions"])
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	dir := t.TempDir()
+	passphrase := filepath.Join(dir, "passphrase")
+	os.WriteFile(passphrase, []byte("correct horse battery staple\n"), 0600)
+	weak := []string{"-time", "1", "-memory", "256", "-threads", "1"}
+
+	code, sealed, stderr := runCommand(t, "345a\n", append([]string{"seal", "-hex", "-passphrase-file", passphrase}, weak...)...)
+	if code != success {
+		t.Fatalf("seal: exit code %d, stderr %q", code, stderr)
+	}
+	if !strings.HasPrefix(sealed, "seal_") || !strings.HasSuffix(sealed, "\n") {
+		t.Errorf("seal: wrong output: %q", sealed)
+	}
+
+	code, stdout, stderr := runCommand(t, sealed, "open", "-hex", "-passphrase-file", passphrase)
+	if code != success {
+		t.Fatalf("open: exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := stdout, "345a\n"; g != e {
+		t.Errorf("open: wrong output: %q != %q", g, e)
+	}
+
+	t.Setenv("TEST_PASSPHRASE", "correct horse battery staple")
+	code, stdout, stderr = runCommand(t, "", "open", "-passphrase-env", "TEST_PASSPHRASE", strings.TrimSpace(sealed))
+	if code != success {
+		t.Fatalf("open: exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := stdout, "\x34\x5a"; g != e {
+		t.Errorf("open: wrong output: %q != %q", g, e)
+	}
+
+	t.Setenv("TEST_PASSPHRASE", "wrong")
+	code, _, stderr = runCommand(t, sealed, "open", "-passphrase-env", "TEST_PASSPHRASE")
+	if code != corruptInputError {
+		t.Errorf("open: wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := stderr, "zrockford32: wrong passphrase or corrupted sealed code\n"; g != e {
+		t.Errorf("open: wrong diagnostic: %q != %q", g, e)
+	}
+}
+
+func TestSealUsageErrors(t *testing.T) {
+	passphrase := filepath.Join(t.TempDir(), "passphrase")
+	os.WriteFile(passphrase, []byte("passphrase"), 0600)
+
+	for _, args := range [][]string{
+		{"seal"},
+		{"seal", "-passphrase-file", passphrase, "secret"},
+		{"seal", "-passphrase-file", passphrase, "-passphrase-env", "HOME"},
+		{"seal", "-passphrase-env", "ZROCKFORD32_UNSET_VARIABLE"},
+		{"seal", "-passphrase-file", passphrase, "-time", "256"},
+		{"seal", "-passphrase-file", passphrase, "-memory", "1000"},
+		{"seal", "-passphrase-file", passphrase, "-threads", "0"},
+		{"open", "-passphrase-file", passphrase, "a", "b"},
+		{"open", "-passphrase-file", passphrase, "-max-threads", "256", "a"},
+	} {
+		if code, _, _ := runCommand(t, "", args...); code != usageError {
+			t.Errorf("%v: wrong exit code: %d != %d", args, code, usageError)
+		}
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "68656c6c6f\n", "split", "-hex", "-n", "5", "-k", "3")
+	if code != success {
+		t.Fatalf("split: exit code %d, stderr %q", code, stderr)
+	}
+
+	shares := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(shares) != 5 {
+		t.Fatalf("split: wrong output: %q", stdout)
+	}
+
+	code, stdout, stderr = runCommand(t, "", "combine", shares[4], shares[0], shares[2])
+	if code != success {
+		t.Fatalf("combine: exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := stdout, "hello"; g != e {
+		t.Errorf("combine: wrong output: %q != %q", g, e)
+	}
+
+	code, stdout, stderr = runCommand(t, shares[1]+"\n\n"+shares[3]+"\n"+shares[2]+"\n", "combine", "-base64")
+	if code != success {
+		t.Fatalf("combine: exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := stdout, "aGVsbG8=\n"; g != e {
+		t.Errorf("combine: wrong output: %q != %q", g, e)
+	}
+
+	code, _, stderr = runCommand(t, "", "combine", shares[0], shares[1])
+	if code != corruptInputError {
+		t.Errorf("combine: wrong exit code: %d != %d", code, corruptInputError)
+	}
+	if g, e := stderr, "zrockford32: not enough shares to recover the secret\n"; g != e {
+		t.Errorf("combine: wrong diagnostic: %q != %q", g, e)
+	}
+
+	for _, args := range [][]string{
+		{"split", "-n", "2", "-k", "3"},
+		{"split", "-n", "3", "-k", "1"},
+		{"split", "-n", "3", "-k", "2", "secret"},
+	} {
+		if code, _, _ := runCommand(t, "secret", args...); code != usageError {
+			t.Errorf("%v: wrong exit code: %d != %d", args, code, usageError)
+		}
+	}
+}
+
+func TestQR(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "", "encode", "-qr", "png", "-qr-scale", "2", "hello")
+	if code != success {
+		t.Fatalf("png: exit code %d, stderr %q", code, stderr)
+	}
+	img, err := png.Decode(strings.NewReader(stdout))
+	if err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	if g, e := img.Bounds().Dx(), (21+8)*2; g != e {
+		t.Errorf("png: wrong width: %d != %d", g, e)
+	}
+
+	code, stdout, stderr = runCommand(t, "68656c6c6f\n", "encode", "-hex", "-qr", "svg", "-qr-level", "h")
+	if code != success {
+		t.Fatalf("svg: exit code %d, stderr %q", code, stderr)
+	}
+	if !strings.HasPrefix(stdout, "<svg ") || !strings.HasSuffix(stdout, "</svg>\n") {
+		t.Errorf("svg: wrong output: %q", stdout)
+	}
+
+	code, stdout, stderr = runCommand(t, "", "encode", "-qr", "terminal", "hello", "world")
+	if code != success {
+		t.Fatalf("terminal: exit code %d, stderr %q", code, stderr)
+	}
+	if !strings.HasPrefix(stdout, "PB1SA5DX\n") || !strings.Contains(stdout, "\nQ7ZZR5DR\n") {
+		t.Errorf("terminal: wrong output: %q", stdout)
+	}
+
+	code, stdout, stderr = runCommand(t, "", "gen", "-qr", "terminal", "-count", "2")
+	if code != success {
+		t.Fatalf("gen: exit code %d, stderr %q", code, stderr)
+	}
+	if g, e := strings.Count(stdout, "\n"), 2*(1+(21+8+1)/2); g != e {
+		t.Errorf("gen: wrong number of lines: %d != %d", g, e)
+	}
+
+	for _, args := range [][]string{
+		{"encode", "-qr", "png", "hello", "world"},
+		{"encode", "-qr", "bmp", "hello"},
+		{"encode", "-qr", "svg", "-qr-level", "X", "hello"},
+		{"encode", "-qr", "png", "-qr-scale", "0", "hello"},
+		{"encode", "-qr", "svg", "-lines"},
+		{"gen", "-qr", "png", "-count", "2"},
+		{"gen", "-qr", "svg", "-format", "json"},
+	} {
+		if code, _, _ := runCommand(t, "", args...); code != usageError {
+			t.Errorf("%v: wrong exit code: %d != %d", args, code, usageError)
+		}
+	}
+}
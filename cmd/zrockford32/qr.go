@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/checksum0/go-zrockford32/qr"
+)
+
+type qrFlags struct {
+	format *string
+	level  *string
+	scale  *int
+}
+
+func addQRFlags(flags *flag.FlagSet) *qrFlags {
+	return &qrFlags{
+		format: flags.String("qr", "", "Write codes as QR codes instead of text: png, svg or terminal"),
+		level:  flags.String("qr-level", "M", "Error correction level of QR codes: L, M, Q or H"),
+		scale:  flags.Int("qr-scale", 8, "Pixels per module of PNG QR codes"),
+	}
+}
+
+func (f *qrFlags) enabled() bool {
+	return *f.format != ""
+}
+
+// check validates the flags for writing count codes: PNG and SVG output hold a
+// single image, while terminal output can follow each code with its QR code.
+func (f *qrFlags) check(count int) error {
+	switch *f.format {
+	case "png", "svg":
+		if count != 1 {
+			return fmt.Errorf("-qr %s writes a single code, not %d", *f.format, count)
+		}
+	case "terminal":
+	default:
+		return fmt.Errorf("unknown QR format %q, expected png, svg or terminal", *f.format)
+	}
+
+	if _, err := f.parseLevel(); err != nil {
+		return err
+	}
+	if *f.scale < 1 {
+		return fmt.Errorf("-qr-scale must be positive")
+	}
+
+	return nil
+}
+
+func (f *qrFlags) parseLevel() (qr.Level, error) {
+	switch strings.ToUpper(*f.level) {
+	case "L":
+		return qr.L, nil
+	case "M":
+		return qr.M, nil
+	case "Q":
+		return qr.Q, nil
+	case "H":
+		return qr.H, nil
+	}
+
+	return 0, fmt.Errorf("unknown QR level %q, expected L, M, Q or H", *f.level)
+}
+
+// write writes code to output as a QR code, in the format checked by check.
+func (f *qrFlags) write(output io.Writer, code string) error {
+	level, err := f.parseLevel()
+	if err != nil {
+		return err
+	}
+
+	c, err := qr.Encode(code, level)
+	if err != nil {
+		return err
+	}
+
+	switch *f.format {
+	case "png":
+		return c.WritePNG(output, *f.scale)
+	case "svg":
+		_, err = io.WriteString(output, c.SVG())
+	default:
+		_, err = io.WriteString(output, code+"\n"+c.Terminal(false))
+	}
+
+	return err
+}
+
+// encodeQR encodes every positional value, or the whole input when there are
+// none, and writes the codes as QR codes.
+func encodeQR(common *ioFlags, encodingFlags *encodingFlags, qrFlags *qrFlags, from format, values []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(values) > 0 && *common.input != "" {
+		fmt.Fprintln(stderr, "zrockford32: -input cannot be combined with values")
+		return usageError
+	}
+
+	count := len(values)
+	if count == 0 {
+		count = 1
+	}
+	if err := qrFlags.check(count); err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	c, err := encodingFlags.codec()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	input, output, code := common.open(stdin, stdout, stderr)
+	if code != success {
+		return code
+	}
+
+	if len(values) == 0 {
+		b, err := io.ReadAll(input)
+		if err != nil {
+			return common.finish(input, output, stderr, err)
+		}
+
+		value := string(b)
+		if from != formatRaw {
+			value = strings.TrimRight(value, "\r\n")
+		}
+		values = []string{value}
+	}
+
+	for _, value := range values {
+		var b []byte
+		var s string
+		if b, err = from.parse(c, value); err != nil {
+			break
+		}
+		if s, err = c.encode(b); err != nil {
+			break
+		}
+		if err = qrFlags.write(output, s); err != nil {
+			break
+		}
+	}
+
+	return common.finish(input, output, stderr, err)
+}
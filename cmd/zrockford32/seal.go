@@ -0,0 +1,195 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/checksum0/go-zrockford32/seal"
+)
+
+// Secrets are never taken as positional values, which would show in process
+// listings and shell histories, nor are passphrases.
+
+type passphraseFlags struct {
+	file *string
+	env  *string
+}
+
+func addPassphraseFlags(flags *flag.FlagSet) *passphraseFlags {
+	return &passphraseFlags{
+		file: flags.String("passphrase-file", "", "Read the passphrase from the first line of this file"),
+		env:  flags.String("passphrase-env", "", "Read the passphrase from this environment variable"),
+	}
+}
+
+func (f *passphraseFlags) passphrase() ([]byte, error) {
+	switch {
+	case *f.file != "" && *f.env != "":
+		return nil, errors.New("-passphrase-file cannot be combined with -passphrase-env")
+	case *f.file != "":
+		b, err := os.ReadFile(*f.file)
+		if err != nil {
+			return nil, err
+		}
+		if i := strings.IndexAny(string(b), "\r\n"); i >= 0 {
+			b = b[:i]
+		}
+		if len(b) == 0 {
+			return nil, fmt.Errorf("%s holds an empty passphrase", *f.file)
+		}
+		return b, nil
+	case *f.env != "":
+		value := os.Getenv(*f.env)
+		if value == "" {
+			return nil, fmt.Errorf("environment variable %s is empty or not set", *f.env)
+		}
+		return []byte(value), nil
+	}
+
+	return nil, errors.New("a passphrase is required, use -passphrase-file or -passphrase-env")
+}
+
+func runSeal(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("seal", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	common := addIOFlags(flags)
+	passphraseFlags := addPassphraseFlags(flags)
+	inputFormat := binaryFormatFlags(flags, "Read the secret")
+	groupFlag := flags.Int("group", 4, "Split the code in groups of this many symbols, 0 not to")
+	timeFlag := flags.Uint("time", uint(seal.DefaultParams.Time), "Argon2id passes over memory")
+	memoryFlag := flags.Uint("memory", uint(seal.DefaultParams.Memory), "Argon2id memory in KiB, a power of two")
+	threadsFlag := flags.Uint("threads", uint(seal.DefaultParams.Threads), "Argon2id parallelism")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(stderr, "zrockford32: seal reads the secret from its input, not from values")
+		return usageError
+	}
+
+	from, err := inputFormat()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+	passphrase, err := passphraseFlags.passphrase()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+	if *timeFlag > 255 || *threadsFlag > 255 || *memoryFlag > 1<<32-1 {
+		fmt.Fprintln(stderr, "zrockford32: -time and -threads must be at most 255, -memory at most 4 GiB")
+		return usageError
+	}
+	params := seal.Params{Time: uint8(*timeFlag), Memory: uint32(*memoryFlag), Threads: uint8(*threadsFlag)}
+	if err := params.Validate(); err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	input, output, code := common.open(stdin, stdout, stderr)
+	if code != success {
+		return code
+	}
+
+	return common.finish(input, output, stderr, sealStream(from, passphrase, input, output, seal.WithParams(params), seal.WithGrouping(*groupFlag)))
+}
+
+func sealStream(from format, passphrase []byte, input io.Reader, output io.Writer, opts ...seal.Option) error {
+	b, err := io.ReadAll(input)
+	if err != nil {
+		return err
+	}
+
+	value := string(b)
+	if from != formatRaw {
+		value = strings.TrimRight(value, "\r\n")
+	}
+
+	secret, err := from.parse(nil, value)
+	if err != nil {
+		return err
+	}
+
+	code, err := seal.SealToCode(secret, passphrase, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(output, code+"\n")
+	return err
+}
+
+func runOpen(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("open", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	common := addIOFlags(flags)
+	passphraseFlags := addPassphraseFlags(flags)
+	outputFormat := binaryFormatFlags(flags, "Write the secret")
+	maxTimeFlag := flags.Uint("max-time", uint(seal.DefaultMaxParams.Time), "Highest Argon2id passes over memory to accept from the code")
+	maxMemoryFlag := flags.Uint("max-memory", uint(seal.DefaultMaxParams.Memory), "Highest Argon2id memory in KiB to accept from the code")
+	maxThreadsFlag := flags.Uint("max-threads", uint(seal.DefaultMaxParams.Threads), "Highest Argon2id parallelism to accept from the code")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() > 1 || flags.NArg() == 1 && *common.input != "" {
+		fmt.Fprintln(stderr, "zrockford32: open takes a single code, either as a value or as input")
+		return usageError
+	}
+
+	to, err := outputFormat()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+	passphrase, err := passphraseFlags.passphrase()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	if *maxTimeFlag > 255 || *maxThreadsFlag > 255 || *maxMemoryFlag > 1<<32-1 {
+		fmt.Fprintln(stderr, "zrockford32: -max-time and -max-threads must be at most 255, -max-memory at most 4 GiB")
+		return usageError
+	}
+	max := seal.Params{Time: uint8(*maxTimeFlag), Memory: uint32(*maxMemoryFlag), Threads: uint8(*maxThreadsFlag)}
+
+	input, output, code := common.open(stdin, stdout, stderr)
+	if code != success {
+		return code
+	}
+
+	return common.finish(input, output, stderr, openStream(to, passphrase, flags.Arg(0), input, output, seal.WithMaxParams(max)))
+}
+
+func openStream(to format, passphrase []byte, code string, input io.Reader, output io.Writer, opts ...seal.Option) error {
+	if code == "" {
+		b, err := io.ReadAll(input)
+		if err != nil {
+			return err
+		}
+		code = string(b)
+	}
+
+	secret, err := seal.OpenCode(code, passphrase, opts...)
+	if err != nil {
+		return err
+	}
+
+	s, err := to.format(nil, secret)
+	if err != nil {
+		return err
+	}
+	if to != formatRaw {
+		s += "\n"
+	}
+
+	_, err = io.WriteString(output, s)
+	return err
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/checksum0/go-zrockford32/shamir"
+)
+
+func runSplit(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("split", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	common := addIOFlags(flags)
+	inputFormat := binaryFormatFlags(flags, "Read the secret")
+	nFlag := flags.Int("n", 0, "Number of shares to make")
+	kFlag := flags.Int("k", 0, "Number of shares needed to recover the secret")
+	groupFlag := flags.Int("group", 4, "Split the shares in groups of this many symbols, 0 not to")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(stderr, "zrockford32: split reads the secret from its input, not from values")
+		return usageError
+	}
+	if *kFlag < 2 || *nFlag < *kFlag || *nFlag > 255 {
+		fmt.Fprintln(stderr, "zrockford32: split needs 2 <= -k <= -n <= 255")
+		return usageError
+	}
+
+	from, err := inputFormat()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	input, output, code := common.open(stdin, stdout, stderr)
+	if code != success {
+		return code
+	}
+
+	return common.finish(input, output, stderr, splitStream(from, *nFlag, *kFlag, *groupFlag, input, output))
+}
+
+func splitStream(from format, n, k, group int, input io.Reader, output io.Writer) error {
+	b, err := io.ReadAll(input)
+	if err != nil {
+		return err
+	}
+
+	value := string(b)
+	if from != formatRaw {
+		value = strings.TrimRight(value, "\r\n")
+	}
+
+	secret, err := from.parse(nil, value)
+	if err != nil {
+		return err
+	}
+
+	shares, err := shamir.Split(secret, n, k, shamir.WithGrouping(group))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(output, strings.Join(shares, "\n")+"\n")
+	return err
+}
+
+func runCombine(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("combine", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	common := addIOFlags(flags)
+	outputFormat := binaryFormatFlags(flags, "Write the secret")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() > 0 && *common.input != "" {
+		fmt.Fprintln(stderr, "zrockford32: -input cannot be combined with values")
+		return usageError
+	}
+
+	to, err := outputFormat()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	input, output, code := common.open(stdin, stdout, stderr)
+	if code != success {
+		return code
+	}
+
+	return common.finish(input, output, stderr, combineStream(to, flags.Args(), input, output))
+}
+
+// combineStream recovers the secret from the shares given as values, or else
+// from the lines of the input.
+func combineStream(to format, shares []string, input io.Reader, output io.Writer) error {
+	if len(shares) == 0 {
+		scanner := bufio.NewScanner(input)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				shares = append(shares, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	secret, err := shamir.Combine(shares...)
+	if err != nil {
+		return err
+	}
+
+	s, err := to.format(nil, secret)
+	if err != nil {
+		return err
+	}
+	if to != formatRaw {
+		s += "\n"
+	}
+
+	_, err = io.WriteString(output, s)
+	return err
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+type verification struct {
+	Code        string   `json:"code"`
+	Valid       bool     `json:"valid"`
+	Canonical   bool     `json:"canonical"`
+	Check       *bool    `json:"check,omitempty"`
+	Bits        int      `json:"bits"`
+	Bytes       int      `json:"bytes"`
+	Error       string   `json:"error,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+func runVerify(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	encodingFlags := addEncodingFlags(flags, true)
+	checkFlag := flags.Bool("check", false, "Codes end with a check symbol")
+	jsonFlag := flags.Bool("json", false, "Write one JSON object per code")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	c, err := encodingFlags.codec()
+	if err != nil {
+		fmt.Fprintf(stderr, "zrockford32: %v\n", err)
+		return usageError
+	}
+
+	codes := flags.Args()
+	if len(codes) == 0 {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				codes = append(codes, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return report(stderr, err)
+		}
+	}
+
+	status := success
+	encoder := json.NewEncoder(stdout)
+	for _, code := range codes {
+		v := c.verify(code, *checkFlag)
+		if !v.Valid {
+			status = corruptInputError
+		}
+
+		if *jsonFlag {
+			err = encoder.Encode(v)
+		} else {
+			_, err = fmt.Fprintln(stdout, v.String())
+		}
+		if err != nil {
+			return report(stderr, err)
+		}
+	}
+
+	return status
+}
+
+func (v verification) String() string {
+	fields := []string{v.Code}
+
+	if !v.Valid {
+		fields = append(fields, "invalid")
+		if v.Error != "" {
+			fields = append(fields, v.Error)
+		}
+		if len(v.Suggestions) > 0 {
+			fields = append(fields, "did you mean "+strings.Join(v.Suggestions, ", "))
+		}
+
+		return strings.Join(fields, "\t")
+	}
+
+	fields = append(fields, "valid")
+	if v.Canonical {
+		fields = append(fields, "canonical")
+	} else {
+		fields = append(fields, "non-canonical")
+	}
+	fields = append(fields, fmt.Sprintf("%d bits", v.Bits), fmt.Sprintf("%d bytes", v.Bytes))
+
+	return strings.Join(fields, "\t")
+}
+
+// strip removes the ignored characters from code.
+func (c *codec) strip(code string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(c.ignoreChars, r) {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+func (c *codec) verify(code string, check bool) verification {
+	v := verification{Code: code}
+
+	// Report illegal characters at their offset in the code as given, before
+	// ignored characters are stripped.
+	if _, err := c.decoding.DecodeString(code); err != nil {
+		v.Error = err.Error()
+		v.Suggestions = c.suggest(code, check)
+		return v
+	}
+
+	payload := c.strip(code)
+	if check {
+		ok := true
+		v.Check = &ok
+
+		stripped, err := c.decoding.VerifyCheckSymbol(payload)
+		switch {
+		case errors.Is(err, zrockford32.ErrCheckSymbol):
+			ok = false
+			payload = payload[:len(payload)-1]
+			v.Error = "check symbol mismatch"
+		case err != nil:
+			v.Error = err.Error()
+			v.Suggestions = c.suggest(code, check)
+			return v
+		default:
+			payload = stripped
+		}
+	}
+
+	decoded, err := c.decode(payload)
+	if err != nil {
+		v.Error = err.Error()
+		v.Suggestions = c.suggest(code, check)
+		return v
+	}
+
+	v.Valid = v.Check == nil || *v.Check
+	v.Bytes = len(decoded)
+	if c.bits < 0 {
+		v.Bits = len(decoded) * 8
+		v.Canonical = c.decoding.IsCanonical(payload)
+	} else {
+		v.Bits = c.bits
+		v.Canonical = c.decoding.IsCanonicalBits(payload, c.bits)
+	}
+
+	if !v.Valid {
+		v.Suggestions = c.suggest(code, check)
+	}
+
+	return v
+}
+
+// suggest proposes the codes a mistyped one was most likely meant to be, only
+// the likeliest one unless a check symbol narrows down the candidates. Without
+// a check symbol or a bit count, nearly every candidate is valid and none is
+// worth proposing.
+func (c *codec) suggest(code string, check bool) []string {
+	if !check && c.bits < 0 {
+		return nil
+	}
+
+	opts := zrockford32.SuggestOptions{CheckSymbol: check, Max: 1}
+	if check {
+		opts.Max = 5
+	}
+
+	if c.bits >= 0 {
+		opts.Valid = func(candidate string) bool {
+			if check {
+				candidate = candidate[:len(candidate)-1]
+			}
+			return c.decoding.IsCanonicalBits(candidate, c.bits)
+		}
+	}
+
+	return c.decoding.Suggest(c.strip(code), opts)
+}
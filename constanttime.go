@@ -0,0 +1,113 @@
+package zrockford32
+
+import "io"
+
+// Constant time
+//
+// The table lookups of Encode and Decode depend on the symbols they process,
+// and Decode stops at the first invalid one, both of which leak timing about
+// the data through cache and branch behaviour. Encodings returned by
+// WithConstantTime instead compare each symbol against the whole alphabet with
+// masks, and validate their whole input before reporting an error, at the cost
+// of speed. Only the positions of ignored characters, which belong to the
+// layout of a code rather than to its content, are not hidden.
+//
+// This applies to Encode, Decode, their Bits and String variants and the
+// streaming encoder, but not to the streaming decoder nor to the other helpers
+// of the package, which still use tables.
+
+// WithConstantTime returns a copy of e whose encoding and decoding run in time
+// independent of the data, for use with secret material.
+func (e *Encoding) WithConstantTime() *Encoding {
+	c := *e
+	c.constantTime = true
+
+	return &c
+}
+
+// ctEq returns 0xFF if a equals b and 0 otherwise, without branching.
+func ctEq(a, b byte) byte {
+	return byte((uint32(a^b) - 1) >> 8)
+}
+
+// ctSymbol returns the symbol for the 5-bit value v.
+func (e *Encoding) ctSymbol(v byte) byte {
+	c := byte(0)
+	for i := 0; i < len(e.encoder); i++ {
+		c |= e.encoder[i] & ctEq(byte(i), v)
+	}
+
+	return c
+}
+
+func decodeConstantTime[S string | []byte](e *Encoding, dst []byte, src S, bits int) (int, error) {
+	// The characters accepted by the encoding are gathered first, from the
+	// decode map only, so that each input character can be compared against
+	// all of them.
+	var chars, values, ignored [256]byte
+	n := 0
+	for c := 0; c < len(e.decodeMap); c++ {
+		if d := e.decodeMap[c]; d != invalidSymbol {
+			chars[n], values[n] = byte(c), d
+			if d == ignoredSymbol {
+				values[n], ignored[n] = 0, 0xFF
+			}
+			n++
+		}
+	}
+
+	off := 0
+	bad, seenBad := 0, byte(0)
+	short := false
+
+	var dbuffer [8]byte
+	j := 0
+	for i := 0; i < len(src); i++ {
+		in := src[i]
+		d, valid, skip := byte(0), byte(0), byte(0)
+		for k := 0; k < n; k++ {
+			m := ctEq(in, chars[k])
+			d |= values[k] & m
+			valid |= m
+			skip |= ignored[k] & m
+		}
+
+		if skip != 0 {
+			continue
+		}
+
+		// Remember the offset of the first invalid symbol, and go on with a
+		// zero value in its place.
+		first := ^valid &^ seenBad
+		mask := int(int8(first))
+		bad = bad&^mask | i&mask
+		seenBad |= first
+
+		dbuffer[j] = d
+		j++
+		if j == 8 {
+			n, ok := decodeBlock(dst[off:], &dbuffer, j, bits)
+			short = short || !ok
+			off += n
+			bits -= 40
+			dbuffer, j = [8]byte{}, 0
+		}
+	}
+
+	if j > 0 {
+		n, ok := decodeBlock(dst[off:], &dbuffer, j, bits)
+		short = short || !ok
+		off += n
+	}
+
+	if seenBad != 0 {
+		Zeroize(dst[:off])
+		return 0, CorruptInputError(bad)
+	}
+	if short {
+		Zeroize(dst[:off])
+		return 0, io.ErrShortBuffer
+	}
+
+	return off, nil
+}
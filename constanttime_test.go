@@ -0,0 +1,173 @@
+package zrockford32_test
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func TestConstantTimeBits(t *testing.T) {
+	for _, tests := range []struct {
+		encoding *zrockford32.Encoding
+		cases    []bitTestCase
+	}{
+		{zrockford32.StdEncoding.WithConstantTime(), bitTestsStd},
+		{zrockford32.LwrEncoding.WithConstantTime(), bitTestsLwr},
+	} {
+		for _, tc := range tests.cases {
+			if g, e := tests.encoding.EncodeBitsToString(tc.decoded, tc.bits), tc.encoded; g != e {
+				t.Errorf("EncodeBitsToString %v %d wrong result: %q != %q", tc.decoded, tc.bits, g, e)
+			}
+
+			decoded, err := tests.encoding.DecodeBitsString(tc.encoded, tc.bits)
+			if err != nil {
+				t.Errorf("DecodeBitsString %q: error: %v", tc.encoded, err)
+				continue
+			}
+			if g, e := decoded, tc.decoded; !bytes.Equal(g, e) {
+				t.Errorf("DecodeBitsString %q wrong result: %v != %v", tc.encoded, g, e)
+			}
+		}
+	}
+}
+
+func TestConstantTimeMatchesTables(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	ct := zrockford32.StdEncoding.WithConstantTime()
+
+	for n := 0; n < 100; n++ {
+		src := make([]byte, n)
+		r.Read(src)
+
+		s := zrockford32.StdEncoding.EncodeToString(src)
+		if g := ct.EncodeToString(src); g != s {
+			t.Errorf("EncodeToString %x wrong result: %q != %q", src, g, s)
+		}
+
+		decoded, err := ct.DecodeString(s)
+		if err != nil || !bytes.Equal(decoded, src) {
+			t.Errorf("DecodeString %q wrong result: %x, %v", s, decoded, err)
+		}
+	}
+}
+
+func TestConstantTimeTolerant(t *testing.T) {
+	ct := zrockford32.StdEncoding.WithIgnoreCase().WithIgnoredChars("-").WithConstantTime()
+
+	decoded, err := ct.DecodeString("pb1s-A5DX-f008-Q551-pt1y-w")
+	if err != nil {
+		t.Fatalf("DecodeString: error: %v", err)
+	}
+	if g, e := string(decoded), "hello, world\n"; g != e {
+		t.Errorf("DecodeString wrong result: %q != %q", g, e)
+	}
+}
+
+func TestConstantTimeBad(t *testing.T) {
+	ct := zrockford32.StdEncoding.WithIgnoredChars("-").WithConstantTime()
+
+	for _, tc := range []struct {
+		input  string
+		offset int64
+	}{
+		{"F00!BAR", 3},
+		{"!", 0},
+		{"PB1S-A5DX-F0!8-Q5!1", 12},
+		{"PB1SA5DXF008Q551PT1YW!", 21},
+	} {
+		n, err := ct.Decode(make([]byte, 32), []byte(tc.input))
+		if g, e := err, zrockford32.CorruptInputError(tc.offset); g != e {
+			t.Errorf("Decode %q: wrong error: %v != %v", tc.input, g, e)
+		}
+		if n != 0 {
+			t.Errorf("Decode %q: wrong count: %d", tc.input, n)
+		}
+	}
+}
+
+// TestConstantTimeTiming compares the decoding time of two classes of inputs
+// with Welch's t-test, in the manner of dudect. It is only run when
+// ZROCKFORD32_TIMING is set, as it takes a while and is sensitive to noise.
+func TestConstantTimeTiming(t *testing.T) {
+	if os.Getenv("ZROCKFORD32_TIMING") == "" {
+		t.Skip("set ZROCKFORD32_TIMING to run the timing test")
+	}
+
+	const samples = 20000
+	const batch = 50
+
+	r := rand.New(rand.NewSource(1))
+	fixed := bytes.Repeat([]byte("Y"), 64)
+	random := make([]byte, 64)
+	invalid := append([]byte("!"), fixed[1:]...)
+	dst := make([]byte, 40)
+
+	for _, tc := range []struct {
+		name     string
+		encoding *zrockford32.Encoding
+		other    func() []byte
+	}{
+		{"random symbols", zrockford32.StdEncoding.WithConstantTime(), func() []byte {
+			for i := range random {
+				random[i] = "YBNDRFG8EJKMCPQX0T1VW2SZA345H769"[r.Intn(32)]
+			}
+			return random
+		}},
+		{"invalid symbol", zrockford32.StdEncoding.WithConstantTime(), func() []byte { return invalid }},
+	} {
+		var times [2][]float64
+		for i := 0; i < samples; i++ {
+			class := r.Intn(2)
+			input := fixed
+			if class == 1 {
+				input = tc.other()
+			}
+
+			start := time.Now()
+			for j := 0; j < batch; j++ {
+				tc.encoding.Decode(dst, input)
+			}
+			times[class] = append(times[class], float64(time.Since(start)))
+		}
+
+		if score := welch(times[0], times[1]); math.Abs(score) > 4.5 {
+			t.Errorf("%s: timing differs from fixed input, t = %.2f", tc.name, score)
+		} else {
+			t.Logf("%s: t = %.2f", tc.name, score)
+		}
+	}
+}
+
+func welch(a, b []float64) float64 {
+	meanA, varA := meanVariance(a)
+	meanB, varB := meanVariance(b)
+
+	return (meanA - meanB) / math.Sqrt(varA/float64(len(a))+varB/float64(len(b)))
+}
+
+func meanVariance(x []float64) (float64, float64) {
+	// Drop the slowest tenth of the samples, which are mostly interrupted by
+	// the scheduler or the garbage collector.
+	sorted := append([]float64(nil), x...)
+	sort.Float64s(sorted)
+	sorted = sorted[:len(sorted)*9/10]
+
+	mean := 0.0
+	for _, v := range sorted {
+		mean += v
+	}
+	mean /= float64(len(sorted))
+
+	variance := 0.0
+	for _, v := range sorted {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return mean, variance / float64(len(sorted)-1)
+}
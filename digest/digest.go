@@ -0,0 +1,233 @@
+// Package digest names content by its hash encoded with zrockford32.
+//
+// Plain digests are the encoded hash, optionally truncated to a number of bits,
+// and only make sense along with the algorithm which produced them.
+// Self-describing digests are encoded multihashes instead: a varint naming the
+// algorithm, a varint holding the length of the hash, and the hash itself,
+// possibly truncated, so that VerifyDigest can check content against them
+// without any other information.
+package digest
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/checksum0/go-zrockford32"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrUnknown  = errors.New("unknown hash algorithm")
+	ErrInvalid  = errors.New("invalid multihash")
+	ErrMismatch = errors.New("content does not match the digest")
+	ErrBits     = errors.New("digest cannot be truncated to that number of bits")
+)
+
+// MinDigestSize is the shortest hash, in bytes, that Sum produces and
+// VerifyDigest accepts: 128 bits leave 64 bits of collision resistance.
+const MinDigestSize = 16
+
+// HashString resets h, hashes data with it and returns the digest encoded with
+// the standard encoding.
+func HashString(h hash.Hash, data []byte) string {
+	h.Reset()
+	h.Write(data)
+
+	return zrockford32.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// HashStringBits is like HashString, but only encodes the first bits bits of
+// the digest, and returns ErrBits unless 0 < bits <= h.Size()*8. Truncating a
+// digest to n bits leaves about n/2 bits of collision resistance.
+func HashStringBits(h hash.Hash, data []byte, bits int) (string, error) {
+	if bits <= 0 || bits > h.Size()*8 {
+		return "", ErrBits
+	}
+
+	h.Reset()
+	h.Write(data)
+
+	return zrockford32.StdEncoding.EncodeBitsToString(h.Sum(nil), bits), nil
+}
+
+// HashWriter hashes what is written to it, and returns the digest encoded.
+type HashWriter struct {
+	hash     hash.Hash
+	encoding *zrockford32.Encoding
+}
+
+// NewHashWriter returns a writer hashing with h and encoding the digest with
+// encoding, or the standard encoding if it is nil.
+func NewHashWriter(h hash.Hash, encoding *zrockford32.Encoding) *HashWriter {
+	if encoding == nil {
+		encoding = zrockford32.StdEncoding
+	}
+
+	return &HashWriter{hash: h, encoding: encoding}
+}
+
+func (w *HashWriter) Write(p []byte) (int, error) {
+	return w.hash.Write(p)
+}
+
+// Sum returns the encoded digest of what was written so far.
+func (w *HashWriter) Sum() string {
+	return w.encoding.EncodeToString(w.hash.Sum(nil))
+}
+
+// SumBits returns the first bits bits of the digest of what was written so
+// far, encoded, or ErrBits unless 0 < bits <= the size of the hash in bits.
+func (w *HashWriter) SumBits(bits int) (string, error) {
+	if bits <= 0 || bits > w.hash.Size()*8 {
+		return "", ErrBits
+	}
+
+	return w.encoding.EncodeBitsToString(w.hash.Sum(nil), bits), nil
+}
+
+// Self-describing digests
+
+// Algorithm is a hash function with its multihash code.
+type Algorithm struct {
+	Name string
+	Code uint64
+	New  func() hash.Hash
+}
+
+var (
+	SHA256     = &Algorithm{Name: "sha2-256", Code: 0x12, New: sha256.New}
+	SHA512     = &Algorithm{Name: "sha2-512", Code: 0x13, New: sha512.New}
+	SHA3_256   = &Algorithm{Name: "sha3-256", Code: 0x16, New: sha3.New256}
+	SHA3_512   = &Algorithm{Name: "sha3-512", Code: 0x14, New: sha3.New512}
+	BLAKE2b256 = &Algorithm{Name: "blake2b-256", Code: 0xb220, New: newBLAKE2b256}
+)
+
+var algorithms = []*Algorithm{SHA256, SHA512, SHA3_256, SHA3_512, BLAKE2b256}
+
+func newBLAKE2b256() hash.Hash {
+	// Only a key longer than 64 bytes makes New256 fail.
+	h, _ := blake2b.New256(nil)
+	return h
+}
+
+// LookupAlgorithm returns the algorithm with the given multihash code.
+func LookupAlgorithm(code uint64) (*Algorithm, bool) {
+	for _, a := range algorithms {
+		if a.Code == code {
+			return a, true
+		}
+	}
+
+	return nil, false
+}
+
+// Multihash returns the multihash of digest, computed by a.
+func (a *Algorithm) Multihash(digest []byte) []byte {
+	b := make([]byte, 2*binary.MaxVarintLen64, 2*binary.MaxVarintLen64+len(digest))
+	n := binary.PutUvarint(b, a.Code)
+	n += binary.PutUvarint(b[n:], uint64(len(digest)))
+
+	return append(b[:n], digest...)
+}
+
+// ParseMultihash returns the algorithm and the digest of a multihash.
+func ParseMultihash(b []byte) (*Algorithm, []byte, error) {
+	code, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, nil, ErrInvalid
+	}
+	b = b[n:]
+
+	size, n := binary.Uvarint(b)
+	if n <= 0 || size == 0 || uint64(len(b)-n) != size {
+		return nil, nil, ErrInvalid
+	}
+	b = b[n:]
+
+	a, ok := LookupAlgorithm(code)
+	if !ok {
+		return nil, nil, ErrUnknown
+	}
+	if len(b) > a.New().Size() {
+		return nil, nil, ErrInvalid
+	}
+
+	return a, b, nil
+}
+
+// Sum returns the self-describing digest of data, its multihash encoded with
+// the standard encoding. The hash is truncated to size bytes if size is
+// positive, which must then be at least MinDigestSize.
+func (a *Algorithm) Sum(data []byte, size int) (string, error) {
+	h := a.New()
+	h.Write(data)
+
+	return a.encode(h, size)
+}
+
+// SumReader is like Sum, but hashes the content of r.
+func (a *Algorithm) SumReader(r io.Reader, size int) (string, error) {
+	h := a.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return a.encode(h, size)
+}
+
+func (a *Algorithm) encode(h hash.Hash, size int) (string, error) {
+	digest := h.Sum(nil)
+	if size > len(digest) {
+		return "", errors.New("digest cannot be truncated to more than the size of the hash")
+	}
+	if size > 0 && size < MinDigestSize {
+		return "", errors.New("digest cannot be truncated to less than MinDigestSize bytes")
+	}
+	if size > 0 {
+		digest = digest[:size]
+	}
+
+	return zrockford32.StdEncoding.EncodeToString(a.Multihash(digest)), nil
+}
+
+// Parse returns the algorithm and the digest of a self-describing digest, in
+// either case.
+func Parse(code string) (*Algorithm, []byte, error) {
+	b, err := zrockford32.StdEncoding.WithIgnoreCase().DecodeString(code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ParseMultihash(b)
+}
+
+// VerifyDigest hashes the content of r with the algorithm of the
+// self-describing digest code, and returns ErrMismatch unless it matches.
+// Digests truncated to less than MinDigestSize bytes are too easy to forge and
+// are refused with ErrInvalid, although ParseMultihash accepts them.
+func VerifyDigest(code string, r io.Reader) error {
+	a, digest, err := Parse(code)
+	if err != nil {
+		return err
+	}
+	if len(digest) < MinDigestSize {
+		return ErrInvalid
+	}
+
+	h := a.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(h.Sum(nil)[:len(digest)], digest) != 1 {
+		return ErrMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package digest_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+	"github.com/checksum0/go-zrockford32/digest"
+)
+
+// The SHA-256 hash of "hello".
+const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestHashString(t *testing.T) {
+	h := sha256.New()
+	h.Write([]byte("discarded"))
+
+	if g, e := digest.HashString(h, []byte("hello")), "FV3R5Q19SNT0HJZE8C2CMQXNVAPTC81HD6VWRZVVY03SFRHMVY1Y"; g != e {
+		t.Errorf("HashString wrong result: %q != %q", g, e)
+	}
+
+	code, err := digest.HashStringBits(h, []byte("hello"), 80)
+	if err != nil {
+		t.Fatalf("HashStringBits: error: %v", err)
+	}
+	if g, e := code, "FV3R5Q19SNT0HJZE"; g != e {
+		t.Errorf("HashStringBits wrong result: %q != %q", g, e)
+	}
+
+	for _, bits := range []int{-1, 0, 257, 300} {
+		if _, err := digest.HashStringBits(h, []byte("hello"), bits); err != digest.ErrBits {
+			t.Errorf("HashStringBits %d: wrong error: %v", bits, err)
+		}
+	}
+}
+
+func TestHashWriter(t *testing.T) {
+	w := digest.NewHashWriter(sha256.New(), nil)
+	io.WriteString(w, "hel")
+	io.WriteString(w, "lo")
+
+	if g, e := w.Sum(), digest.HashString(sha256.New(), []byte("hello")); g != e {
+		t.Errorf("Sum wrong result: %q != %q", g, e)
+	}
+
+	w = digest.NewHashWriter(sha256.New(), zrockford32.LwrEncoding)
+	io.WriteString(w, "hello")
+
+	code, err := w.SumBits(80)
+	if err != nil {
+		t.Fatalf("SumBits: error: %v", err)
+	}
+	if g, e := code, "fv3r5q19snt0hjze"; g != e {
+		t.Errorf("SumBits wrong result: %q != %q", g, e)
+	}
+
+	for _, bits := range []int{-1, 0, 257} {
+		if _, err := w.SumBits(bits); err != digest.ErrBits {
+			t.Errorf("SumBits %d: wrong error: %v", bits, err)
+		}
+	}
+}
+
+func TestSum(t *testing.T) {
+	for _, tc := range []struct {
+		algorithm *digest.Algorithm
+		size      int
+		code      string
+	}{
+		{digest.SHA256, 0, "NE0N3H1PZJX5BEAQR5WDSKSFZ8TJHGASD3QB9J4NM330EC5N1QF30JY"},
+		{digest.SHA256, 16, "NEEN3H1PZJX5BEAQR5WDSKSFZ8TJH"},
+		{digest.SHA512, 16, "NCEJSHQ1R16SFH5AMSMPE4SV7E6ZG"},
+		{digest.SHA3_256, 0, "NA0DGQF6PF82BTXV8NYWVBSP6BWGEW7ETNHR601PXR2XJQJYRQCX8R0"},
+		{digest.SHA3_512, 16, "N0E8M2J8APWXF59EJDSXPCDVWPVSQ"},
+		{digest.BLAKE2b256, 0, "WD1YREB1JZ80R9QWWCFJGMNRDH5FWJXEPCMV557RZD1A11BFGTA50G513H"},
+		{digest.BLAKE2b256, 16, "WD1YRRB1JZ80R9QWWCFJGMNRDH5FWJXE"},
+	} {
+		code, err := tc.algorithm.Sum([]byte("hello"), tc.size)
+		if err != nil {
+			t.Errorf("Sum %s %d: error: %v", tc.algorithm.Name, tc.size, err)
+			continue
+		}
+		if g, e := code, tc.code; g != e {
+			t.Errorf("Sum %s %d wrong result: %q != %q", tc.algorithm.Name, tc.size, g, e)
+		}
+
+		code, err = tc.algorithm.SumReader(strings.NewReader("hello"), tc.size)
+		if err != nil {
+			t.Errorf("SumReader %s %d: error: %v", tc.algorithm.Name, tc.size, err)
+			continue
+		}
+		if g, e := code, tc.code; g != e {
+			t.Errorf("SumReader %s %d wrong result: %q != %q", tc.algorithm.Name, tc.size, g, e)
+		}
+	}
+
+	for _, size := range []int{1, digest.MinDigestSize - 1, 33} {
+		if _, err := digest.SHA256.Sum([]byte("hello"), size); err == nil {
+			t.Errorf("Sum %d: succeeded", size)
+		}
+	}
+}
+
+func TestMultihash(t *testing.T) {
+	a, d, err := digest.Parse("NE0N3H1PZJX5BEAQR5WDSKSFZ8TJHGASD3QB9J4NM330EC5N1QF30JY")
+	if err != nil {
+		t.Fatalf("Parse: error: %v", err)
+	}
+	if g, e := a.Name, digest.SHA256.Name; g != e {
+		t.Errorf("Parse algorithm wrong result: %q != %q", g, e)
+	}
+	if g, e := hex.EncodeToString(d), helloSHA256; g != e {
+		t.Errorf("Parse digest wrong result: %q != %q", g, e)
+	}
+
+	// The multihash of SHA-256 starts with its code, 0x12, and length, 0x20.
+	if g, e := hex.EncodeToString(digest.SHA256.Multihash(d)), "1220"+helloSHA256; g != e {
+		t.Errorf("Multihash wrong result: %q != %q", g, e)
+	}
+
+	for _, tc := range []struct {
+		hex string
+		err error
+	}{
+		{"", digest.ErrInvalid},
+		{"12", digest.ErrInvalid},
+		{"1200", digest.ErrInvalid},
+		{"12022cf2ff", digest.ErrInvalid},
+		{"7f022cf2", digest.ErrUnknown},
+		{"1221" + helloSHA256 + "00", digest.ErrInvalid},
+	} {
+		b, _ := hex.DecodeString(tc.hex)
+		if _, _, err := digest.ParseMultihash(b); err != tc.err {
+			t.Errorf("ParseMultihash %q: wrong error: %v", tc.hex, err)
+		}
+	}
+}
+
+func TestVerifyDigest(t *testing.T) {
+	for _, code := range []string{
+		"NE0N3H1PZJX5BEAQR5WDSKSFZ8TJHGASD3QB9J4NM330EC5N1QF30JY",
+		"neen3h1pzjx5beaqr5wdsksfz8tjh",
+		"WD1YRRB1JZ80R9QWWCFJGMNRDH5FWJXE",
+	} {
+		if err := digest.VerifyDigest(code, strings.NewReader("hello")); err != nil {
+			t.Errorf("VerifyDigest %q: error: %v", code, err)
+		}
+		if err := digest.VerifyDigest(code, strings.NewReader("hello!")); err != digest.ErrMismatch {
+			t.Errorf("VerifyDigest %q: wrong error: %v", code, err)
+		}
+	}
+
+	// SHA-256 truncated to 10 and 1 bytes: both match, but are too short.
+	for _, code := range []string{"NEFN3H1PZJX5BEAQR5WY", "NEY1A"} {
+		if err := digest.VerifyDigest(code, strings.NewReader("hello")); err != digest.ErrInvalid {
+			t.Errorf("VerifyDigest %q: wrong error: %v", code, err)
+		}
+	}
+
+	var corrupt zrockford32.CorruptInputError
+	if err := digest.VerifyDigest("NE0N!", bytes.NewReader(nil)); !errors.As(err, &corrupt) {
+		t.Errorf("VerifyDigest %q: wrong error: %v", "NE0N!", err)
+	}
+}
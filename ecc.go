@@ -0,0 +1,324 @@
+package zrockford32
+
+import "errors"
+
+// Error correction
+//
+// EncodeECC protects the encoded data with Reed-Solomon parity symbols over
+// GF(32), so that up to paritySymbols/2 wrong symbols, or up to paritySymbols
+// unreadable ones marked with '?', can be fixed in each block of 31 symbols.
+// The code starts with a header repeating the parity symbol count three times,
+// so that DecodeECC does not need to be told about it, followed by blocks made
+// of up to 31-paritySymbols data symbols and their parity symbols.
+
+var ErrParitySymbols = errors.New("zrockford32 parity symbol count must be between 1 and 30")
+var ErrUncorrectable = errors.New("zrockford32 too many errors to correct")
+
+const (
+	eccHeaderLen = 3
+	eccBlockLen  = 31
+	eccErasure   = '?'
+)
+
+func (e *Encoding) EncodeECC(src []byte, paritySymbols int) (string, error) {
+	if paritySymbols < 1 || paritySymbols >= eccBlockLen {
+		return "", ErrParitySymbols
+	}
+
+	data := e.EncodeToString(src)
+	k := eccBlockLen - paritySymbols
+	generator := rsGenerator(paritySymbols)
+
+	dst := make([]byte, 0, eccHeaderLen+len(data)+(len(data)+k-1)/k*paritySymbols)
+	for i := 0; i < eccHeaderLen; i++ {
+		dst = append(dst, e.encoder[paritySymbols])
+	}
+
+	for len(data) > 0 {
+		n := min(k, len(data))
+
+		// The parity symbols are the remainder of the division of the data,
+		// shifted by the generator degree, by the generator.
+		parity := make([]byte, paritySymbols)
+		for i := 0; i < n; i++ {
+			feedback := e.decodeMap[data[i]] ^ parity[0]
+			copy(parity, parity[1:])
+			parity[paritySymbols-1] = 0
+			for j := range parity {
+				parity[j] ^= gf32Mul(generator[j+1], feedback)
+			}
+		}
+
+		dst = append(dst, data[:n]...)
+		for _, p := range parity {
+			dst = append(dst, e.encoder[p])
+		}
+		data = data[n:]
+	}
+
+	return string(dst), nil
+}
+
+// DecodeECC returns the data protected by s, and the number of symbols which
+// had to be corrected. Unreadable symbols may be replaced with '?', unless it
+// is part of the alphabet, which lets twice as many of them be corrected as
+// wrong symbols.
+func (e *Encoding) DecodeECC(s string) ([]byte, int, error) {
+	symbols := make([]byte, 0, len(s))
+	erased := make([]bool, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		switch d := e.decodeMap[s[i]]; {
+		case d <= 31:
+			symbols = append(symbols, d)
+			erased = append(erased, false)
+		case d == ignoredSymbol:
+		case s[i] == eccErasure:
+			symbols = append(symbols, 0)
+			erased = append(erased, true)
+		default:
+			return nil, 0, CorruptInputError(i)
+		}
+	}
+
+	if len(symbols) < eccHeaderLen {
+		return nil, 0, ErrUncorrectable
+	}
+
+	paritySymbols, corrected, ok := eccHeader(symbols[:eccHeaderLen], erased[:eccHeaderLen])
+	if !ok || paritySymbols < 1 || paritySymbols >= eccBlockLen {
+		return nil, 0, ErrUncorrectable
+	}
+	if last := (len(symbols) - eccHeaderLen) % eccBlockLen; last != 0 && last <= paritySymbols {
+		return nil, 0, ErrUncorrectable
+	}
+
+	data := make([]byte, 0, len(symbols))
+	for i := eccHeaderLen; i < len(symbols); i += eccBlockLen {
+		end := min(i+eccBlockLen, len(symbols))
+
+		n, ok := rsCorrect(symbols[i:end], erased[i:end], paritySymbols)
+		if !ok {
+			return nil, 0, ErrUncorrectable
+		}
+		corrected += n
+
+		for _, d := range symbols[i : end-paritySymbols] {
+			data = append(data, e.encoder[d])
+		}
+	}
+
+	dst, err := e.DecodeString(string(data))
+	if err != nil {
+		return nil, 0, ErrUncorrectable
+	}
+
+	return dst, corrected, nil
+}
+
+// eccHeader returns the parity symbol count held by the header by majority
+// vote, and how many of its symbols disagree with it.
+func eccHeader(symbols []byte, erased []bool) (int, int, bool) {
+	var votes [32]int
+	for i, d := range symbols {
+		if !erased[i] {
+			votes[d]++
+		}
+	}
+
+	best := 0
+	for d := range votes {
+		if votes[d] > votes[best] {
+			best = d
+		}
+	}
+
+	for d := range votes {
+		if d != best && votes[d] == votes[best] {
+			return 0, 0, false
+		}
+	}
+
+	return best, len(symbols) - votes[best], true
+}
+
+// Reed-Solomon
+//
+// Polynomials are stored highest degree first, as the symbols of a block are,
+// and the roots of the generator are the consecutive powers of the primitive
+// element from alpha^0.
+
+func rsGenerator(degree int) []byte {
+	generator := []byte{1}
+
+	for i := 0; i < degree; i++ {
+		next := make([]byte, len(generator)+1)
+		for j, c := range generator {
+			next[j] ^= c
+			next[j+1] ^= gf32Mul(c, gf32Exp[i])
+		}
+		generator = next
+	}
+
+	return generator
+}
+
+func gf32Inv(a byte) byte {
+	return gf32Exp[31-int(gf32Log[a])]
+}
+
+func gf32Pow(i int) byte {
+	return gf32Exp[(i%31+31)%31]
+}
+
+// rsEval evaluates a polynomial stored lowest degree first.
+func rsEval(p []byte, x byte) byte {
+	y := byte(0)
+	for i := len(p) - 1; i >= 0; i-- {
+		y = gf32Mul(y, x) ^ p[i]
+	}
+
+	return y
+}
+
+// rsCorrect fixes block in place, and returns the number of symbols changed.
+// Locators and the polynomials of the decoder are stored lowest degree first.
+func rsCorrect(block []byte, erased []bool, paritySymbols int) (int, bool) {
+	n := len(block)
+
+	syndromes := make([]byte, paritySymbols)
+	clean := true
+	for i := range syndromes {
+		for _, d := range block {
+			syndromes[i] = gf32Mul(syndromes[i], gf32Exp[i]) ^ d
+		}
+		clean = clean && syndromes[i] == 0
+	}
+
+	erasures := 0
+	for _, e := range erased {
+		if e {
+			erasures++
+		}
+	}
+	if clean {
+		return erasures, true
+	}
+	if erasures > paritySymbols {
+		return 0, false
+	}
+
+	// The errata locator starts as the erasure locator, and Berlekamp-Massey
+	// extends it with the locations of the errors.
+	locator := []byte{1}
+	for j, e := range erased {
+		if e {
+			locator = rsMulLinear(locator, gf32Pow(n-1-j))
+		}
+	}
+
+	previous := append([]byte(nil), locator...)
+	for r := erasures; r < paritySymbols; r++ {
+		delta := syndromes[r]
+		for i := 1; i < len(locator) && i <= r; i++ {
+			delta ^= gf32Mul(locator[i], syndromes[r-i])
+		}
+
+		previous = append([]byte{0}, previous...)
+		if delta == 0 {
+			continue
+		}
+
+		if len(previous) > len(locator) {
+			next := rsScale(previous, delta)
+			previous = rsScale(locator, gf32Inv(delta))
+			locator = next
+		}
+		for i, c := range previous {
+			if i == len(locator) {
+				locator = append(locator, 0)
+			}
+			locator[i] ^= gf32Mul(delta, c)
+		}
+	}
+
+	for len(locator) > 1 && locator[len(locator)-1] == 0 {
+		locator = locator[:len(locator)-1]
+	}
+	length := len(locator) - 1
+	if length < erasures || 2*(length-erasures)+erasures > paritySymbols {
+		return 0, false
+	}
+
+	// The evaluator is the product of the syndromes and the locator, modulo
+	// x^paritySymbols, and the derivative of the locator only keeps its odd
+	// terms in characteristic 2.
+	evaluator := make([]byte, paritySymbols)
+	for i, c := range locator {
+		for j := 0; i+j < paritySymbols; j++ {
+			evaluator[i+j] ^= gf32Mul(c, syndromes[j])
+		}
+	}
+
+	derivative := make([]byte, len(locator))
+	for i := 1; i < len(locator); i += 2 {
+		derivative[i-1] = locator[i]
+	}
+
+	found := 0
+	changed := 0
+	for j := 0; j < n; j++ {
+		x := gf32Pow(n - 1 - j)
+		xInverse := gf32Inv(x)
+		if rsEval(locator, xInverse) != 0 {
+			continue
+		}
+		found++
+
+		denominator := rsEval(derivative, xInverse)
+		if denominator == 0 {
+			return 0, false
+		}
+
+		magnitude := gf32Mul(gf32Mul(x, rsEval(evaluator, xInverse)), gf32Inv(denominator))
+		if magnitude != 0 || erased[j] {
+			changed++
+		}
+		block[j] ^= magnitude
+	}
+	if found != length {
+		return 0, false
+	}
+
+	for i := 0; i < paritySymbols; i++ {
+		s := byte(0)
+		for _, d := range block {
+			s = gf32Mul(s, gf32Exp[i]) ^ d
+		}
+		if s != 0 {
+			return 0, false
+		}
+	}
+
+	return changed, true
+}
+
+func rsScale(p []byte, x byte) []byte {
+	scaled := make([]byte, len(p))
+	for i, c := range p {
+		scaled[i] = gf32Mul(c, x)
+	}
+
+	return scaled
+}
+
+// rsMulLinear multiplies p by 1 - xX.
+func rsMulLinear(p []byte, x byte) []byte {
+	product := make([]byte, len(p)+1)
+	for i, c := range p {
+		product[i] ^= c
+		product[i+1] ^= gf32Mul(c, x)
+	}
+
+	return product
+}
@@ -0,0 +1,142 @@
+package zrockford32_test
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+const eccAlphabet = "YBNDRFG8EJKMCPQX0T1VW2SZA345H769"
+
+func TestECCRoundTrip(t *testing.T) {
+	for _, tc := range byteTestsStd {
+		for _, parity := range []int{1, 4, 10, 30} {
+			code, err := zrockford32.StdEncoding.EncodeECC(tc.decoded, parity)
+			if err != nil {
+				t.Errorf("EncodeECC %q parity %d: error: %v", tc.decoded, parity, err)
+				continue
+			}
+			if len(tc.encoded) <= 31-parity && !strings.HasPrefix(code[3:], tc.encoded) {
+				t.Errorf("EncodeECC %q parity %d: data not kept as is: %q", tc.decoded, parity, code)
+			}
+
+			decoded, corrected, err := zrockford32.StdEncoding.DecodeECC(code)
+			if err != nil {
+				t.Errorf("DecodeECC %q: error: %v", code, err)
+				continue
+			}
+			if !bytes.Equal(decoded, tc.decoded) || corrected != 0 {
+				t.Errorf("DecodeECC %q wrong result: %q, %d != %q, 0", code, decoded, corrected, tc.decoded)
+			}
+		}
+	}
+}
+
+func TestECCCorrectsErrors(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 200; n++ {
+		src := make([]byte, r.Intn(64))
+		r.Read(src)
+		parity := 2 + r.Intn(12)
+
+		code, err := zrockford32.StdEncoding.EncodeECC(src, parity)
+		if err != nil {
+			t.Fatalf("EncodeECC: error: %v", err)
+		}
+
+		// Damage each block within its capacity, mixing wrong and unreadable
+		// symbols, and leave the header alone.
+		damaged := []byte(code)
+		want := 0
+		for start := 3; start < len(damaged); start += 31 {
+			end := start + 31
+			if end > len(damaged) {
+				end = len(damaged)
+			}
+
+			erasures := r.Intn(parity + 1)
+			errors := r.Intn((parity-erasures)/2 + 1)
+			for i, p := range r.Perm(end - start)[:erasures+errors] {
+				if i < erasures {
+					damaged[start+p] = '?'
+				} else {
+					damaged[start+p] = eccAlphabet[(strings.IndexByte(eccAlphabet, damaged[start+p])+1+r.Intn(31))%32]
+				}
+			}
+			want += erasures + errors
+		}
+
+		decoded, corrected, err := zrockford32.StdEncoding.DecodeECC(string(damaged))
+		if err != nil {
+			t.Errorf("DecodeECC %q (from %q): error: %v", damaged, code, err)
+			continue
+		}
+		if !bytes.Equal(decoded, src) {
+			t.Errorf("DecodeECC %q wrong result: %x != %x", damaged, decoded, src)
+		}
+		if corrected != want {
+			t.Errorf("DecodeECC %q wrong correction count: %d != %d", damaged, corrected, want)
+		}
+	}
+}
+
+func TestECCHeader(t *testing.T) {
+	code, _ := zrockford32.StdEncoding.EncodeECC([]byte("hello, world"), 6)
+
+	for _, damaged := range []string{
+		"?" + code[1:],
+		"W" + code[1:],
+		code[:1] + "??" + code[3:],
+	} {
+		decoded, _, err := zrockford32.StdEncoding.DecodeECC(damaged)
+		if err != nil || string(decoded) != "hello, world" {
+			t.Errorf("DecodeECC %q wrong result: %q, %v", damaged, decoded, err)
+		}
+	}
+
+	for _, damaged := range []string{"???" + code[3:], "WE" + code[2:], "YB?" + code[3:]} {
+		if _, _, err := zrockford32.StdEncoding.DecodeECC(damaged); err != zrockford32.ErrUncorrectable {
+			t.Errorf("DecodeECC %q: wrong error: %v", damaged, err)
+		}
+	}
+}
+
+func TestECCIgnoredChars(t *testing.T) {
+	code, _ := zrockford32.StdEncoding.EncodeECC([]byte("hello, world"), 4)
+	grouped := zrockford32.Group(code, 4, "-")
+	grouped = grouped[:6] + "?" + grouped[7:]
+
+	decoded, corrected, err := zrockford32.StdEncoding.WithIgnoredChars("-").DecodeECC(grouped)
+	if err != nil || string(decoded) != "hello, world" || corrected != 1 {
+		t.Errorf("DecodeECC %q wrong result: %q, %d, %v", grouped, decoded, corrected, err)
+	}
+}
+
+func TestECCBad(t *testing.T) {
+	for _, parity := range []int{-1, 0, 31} {
+		if _, err := zrockford32.StdEncoding.EncodeECC([]byte("hi"), parity); err != zrockford32.ErrParitySymbols {
+			t.Errorf("EncodeECC parity %d: wrong error: %v", parity, err)
+		}
+	}
+
+	code, _ := zrockford32.StdEncoding.EncodeECC([]byte("hello, world"), 4)
+
+	if _, _, err := zrockford32.StdEncoding.DecodeECC(code[:6] + "!" + code[7:]); err != zrockford32.CorruptInputError(6) {
+		t.Errorf("DecodeECC: wrong error: %v", err)
+	}
+
+	for _, damaged := range []string{
+		"",
+		"RR",
+		code[:len(code)-2],
+		code[:3] + strings.Repeat("?", 5) + code[8:],
+	} {
+		if _, _, err := zrockford32.StdEncoding.DecodeECC(damaged); err != zrockford32.ErrUncorrectable {
+			t.Errorf("DecodeECC %q: wrong error: %v", damaged, err)
+		}
+	}
+}
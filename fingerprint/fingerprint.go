@@ -0,0 +1,170 @@
+// Package fingerprint renders hashes of keys for people to compare, as grouped
+// zrockford32 text, as randomart in the manner of OpenSSH, and as identicons.
+// All three are derived from the same bits, so two keys with matching text
+// also look the same.
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+const groupSize = 4
+
+type Fingerprint struct {
+	hash []byte
+}
+
+// New returns the fingerprint of a hash, which is kept as is.
+func New(hash []byte) *Fingerprint {
+	return &Fingerprint{hash: append([]byte(nil), hash...)}
+}
+
+// Of returns the fingerprint of the SHA-256 hash of key.
+func Of(key []byte) *Fingerprint {
+	sum := sha256.Sum256(key)
+	return New(sum[:])
+}
+
+// String returns the hash encoded with the standard encoding, in groups of
+// four symbols separated by spaces.
+func (f *Fingerprint) String() string {
+	return zrockford32.Group(zrockford32.StdEncoding.EncodeToString(f.hash), groupSize, " ")
+}
+
+// Randomart
+
+const (
+	artWidth   = 17
+	artHeight  = 9
+	artSymbols = " .o+=*BOX@%&#/^"
+)
+
+// Randomart returns the path of the drunken bishop walked over the hash, as
+// drawn by ssh-keygen -lv, with title in the top border when not empty.
+func (f *Fingerprint) Randomart(title string) string {
+	var field [artWidth][artHeight]int
+	x, y := artWidth/2, artHeight/2
+
+	// Each byte moves the bishop four times, diagonally, in the direction
+	// given by each pair of bits from the lowest ones, sliding along walls.
+	for _, b := range f.hash {
+		for i := 0; i < 4; i++ {
+			if b&1 != 0 {
+				x = min(x+1, artWidth-1)
+			} else {
+				x = max(x-1, 0)
+			}
+			if b&2 != 0 {
+				y = min(y+1, artHeight-1)
+			} else {
+				y = max(y-1, 0)
+			}
+			field[x][y]++
+			b >>= 2
+		}
+	}
+
+	start, end := len(artSymbols), len(artSymbols)+1
+	field[artWidth/2][artHeight/2] = start
+	field[x][y] = end
+
+	var b strings.Builder
+	b.WriteString(border(title))
+	for row := 0; row < artHeight; row++ {
+		b.WriteByte('|')
+		for column := 0; column < artWidth; column++ {
+			switch n := field[column][row]; {
+			case n == start:
+				b.WriteByte('S')
+			case n == end:
+				b.WriteByte('E')
+			default:
+				b.WriteByte(artSymbols[min(n, len(artSymbols)-1)])
+			}
+		}
+		b.WriteString("|\n")
+	}
+	b.WriteString(border(""))
+
+	return b.String()
+}
+
+func border(title string) string {
+	if title == "" {
+		return "+" + strings.Repeat("-", artWidth) + "+\n"
+	}
+
+	title = "[" + title + "]"
+	if len(title) > artWidth {
+		title = title[:artWidth]
+	}
+	left := (artWidth - len(title)) / 2
+
+	return "+" + strings.Repeat("-", left) + title + strings.Repeat("-", artWidth-left-len(title)) + "+\n"
+}
+
+// Identicons
+
+const identiconCells = 5
+
+// Identicon returns a square image of size pixels made of a 5x5 grid, mirrored
+// left to right, whose colour and cells are taken from the hash.
+func (f *Fingerprint) Identicon(size int) image.Image {
+	var bits [4]byte
+	copy(bits[:], f.hash)
+
+	foreground := color.NRGBA{R: bits[0]/2 + 64, G: bits[1]/2 + 64, B: bits[2]/2 + 64, A: 255}
+	background := color.NRGBA{R: 240, G: 240, B: 240, A: 255}
+	palette := color.Palette{background, foreground}
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), palette)
+
+	// The 15 cells of the left half and the middle column take one bit each,
+	// from the fourth byte of the hash on.
+	var cells [identiconCells][identiconCells]bool
+	for i := 0; i < identiconCells*(identiconCells+1)/2; i++ {
+		row, column := i%identiconCells, i/identiconCells
+		byteIndex := 3 + i/8
+		on := byteIndex < len(f.hash) && f.hash[byteIndex]>>uint(i%8)&1 != 0
+		cells[row][column] = on
+		cells[row][identiconCells-1-column] = on
+	}
+
+	for py := 0; py < size; py++ {
+		for px := 0; px < size; px++ {
+			if cells[py*identiconCells/size][px*identiconCells/size] {
+				img.SetColorIndex(px, py, 1)
+			}
+		}
+	}
+
+	return img
+}
+
+// WritePNG writes the identicon of size pixels to w as a PNG image.
+func (f *Fingerprint) WritePNG(w io.Writer, size int) error {
+	return png.Encode(w, f.Identicon(size))
+}
+
+func min(a, b int) int {
+	if a <= b {
+		return a
+	}
+
+	return b
+}
+
+func max(a, b int) int {
+	if a >= b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,86 @@
+package fingerprint_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"image/png"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32/fingerprint"
+)
+
+// The example of "The drunken bishop: An analysis of the OpenSSH fingerprint
+// visualization algorithm".
+const bishopHash = "fc94b0c1e5b0987c5843997697ee9fb7"
+
+func TestString(t *testing.T) {
+	hash, _ := hex.DecodeString(bishopHash)
+	if g, e := fingerprint.New(hash).String(), "91KM B0XF SNC8 ASND VF5J X5W9 SH"; g != e {
+		t.Errorf("String wrong result: %q != %q", g, e)
+	}
+
+	if g, e := fingerprint.Of([]byte("hello, world")).String(), fingerprint.Of([]byte("hello, world")).String(); g != e {
+		t.Errorf("Of is not deterministic: %q != %q", g, e)
+	}
+}
+
+func TestRandomart(t *testing.T) {
+	hash, _ := hex.DecodeString(bishopHash)
+
+	const art = "" +
+		"+------[MD5]------+\n" +
+		"|       .=o.  .   |\n" +
+		"|     . *+*. o    |\n" +
+		"|      =.*..o     |\n" +
+		"|       o + ..    |\n" +
+		"|        S o.     |\n" +
+		"|         o  .    |\n" +
+		"|          .  . . |\n" +
+		"|              o .|\n" +
+		"|               E.|\n" +
+		"+-----------------+\n"
+	if g, e := fingerprint.New(hash).Randomart("MD5"), art; g != e {
+		t.Errorf("Randomart wrong result:\n%s!=\n%s", g, e)
+	}
+}
+
+func TestIdenticon(t *testing.T) {
+	f := fingerprint.Of([]byte("hello, world"))
+
+	var buffer bytes.Buffer
+	if err := f.WritePNG(&buffer, 50); err != nil {
+		t.Fatalf("WritePNG: error: %v", err)
+	}
+
+	img, err := png.Decode(&buffer)
+	if err != nil {
+		t.Fatalf("png.Decode: error: %v", err)
+	}
+	if g, e := img.Bounds().Dx(), 50; g != e {
+		t.Errorf("wrong size: %d != %d", g, e)
+	}
+
+	distinct := false
+	for y := 0; y < 50; y++ {
+		for x := 0; x < 50; x++ {
+			if img.At(x, y) != img.At(49-x, y) {
+				t.Fatalf("identicon not mirrored at %d,%d", x, y)
+			}
+			distinct = distinct || img.At(x, y) != img.At(0, 0)
+		}
+	}
+	if !distinct {
+		t.Errorf("identicon is a single colour")
+	}
+
+	other := fingerprint.Of([]byte("hello, world!")).Identicon(50)
+	same := true
+	for y := 0; y < 50 && same; y++ {
+		for x := 0; x < 50 && same; x++ {
+			same = img.At(x, y) == other.At(x, y)
+		}
+	}
+	if same {
+		t.Errorf("identicons of different keys are identical")
+	}
+}
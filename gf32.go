@@ -0,0 +1,33 @@
+package zrockford32
+
+// GF(32)
+//
+// Symbols of an alphabet are treated as elements of GF(2^5), built from the
+// primitive polynomial x^5 + x^2 + 1.
+
+const gf32Poly = 0x25
+
+var gf32Exp [62]byte
+var gf32Log [32]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 31; i++ {
+		gf32Exp[i] = x
+		gf32Exp[i+31] = x
+		gf32Log[x] = byte(i)
+
+		x <<= 1
+		if x&0x20 != 0 {
+			x ^= gf32Poly
+		}
+	}
+}
+
+func gf32Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return gf32Exp[int(gf32Log[a])+int(gf32Log[b])]
+}
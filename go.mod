@@ -1,3 +1,7 @@
 module github.com/checksum0/go-zrockford32
 
 go 1.18
+
+require golang.org/x/crypto v0.17.0
+
+require golang.org/x/sys v0.15.0 // indirect
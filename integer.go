@@ -0,0 +1,166 @@
+package zrockford32
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Integers
+//
+// Integers are encoded most significant symbol first, in base 32, using the
+// encoding's alphabet as digits. Leading zero symbols are never produced, so
+// the result is the shortest string that represents the value, unless a fixed
+// width is requested, in which case the value is left-filled with the zero
+// symbol of the alphabet. Likewise, only the fixed width decoder accepts
+// leading zero symbols, so that each value has a single variable width code.
+
+var ErrOverflow = errors.New("zrockford32 value overflows uint64")
+var ErrWidth = errors.New("zrockford32 value does not match the requested width")
+
+const bigDigits = "0123456789abcdefghijklmnopqrstuv"
+
+func (e *Encoding) AppendUint64(dst []byte, v uint64) []byte {
+	var buffer [13]byte
+	i := len(buffer)
+
+	for {
+		i--
+		buffer[i] = e.encoder[v&31]
+		v >>= 5
+		if v == 0 {
+			break
+		}
+	}
+
+	return append(dst, buffer[i:]...)
+}
+
+func (e *Encoding) EncodeUint64(v uint64) string {
+	return string(e.AppendUint64(nil, v))
+}
+
+func (e *Encoding) DecodeUint64(s string) (uint64, error) {
+	if len(s) > 1 && e.decodeMap[s[0]] == 0 {
+		return 0, CorruptInputError(0)
+	}
+
+	return e.decodeUint64(s)
+}
+
+func (e *Encoding) decodeUint64(s string) (uint64, error) {
+	if len(s) == 0 {
+		return 0, CorruptInputError(0)
+	}
+
+	var v uint64
+	for i := 0; i < len(s); i++ {
+		d := e.decodeMap[s[i]]
+		if d > 31 {
+			return 0, CorruptInputError(i)
+		}
+		if v>>59 != 0 {
+			return 0, ErrOverflow
+		}
+		v = v<<5 | uint64(d)
+	}
+
+	return v, nil
+}
+
+func (e *Encoding) AppendUintFixed(dst []byte, v uint64, width int) ([]byte, error) {
+	if width <= 0 {
+		return dst, ErrWidth
+	}
+
+	var buffer [13]byte
+	digits := e.AppendUint64(buffer[:0], v)
+	if len(digits) > width {
+		return dst, ErrWidth
+	}
+
+	for i := len(digits); i < width; i++ {
+		dst = append(dst, e.encoder[0])
+	}
+
+	return append(dst, digits...), nil
+}
+
+func (e *Encoding) EncodeUintFixed(v uint64, width int) (string, error) {
+	dst, err := e.AppendUintFixed(nil, v, width)
+	if err != nil {
+		return "", err
+	}
+
+	return string(dst), nil
+}
+
+func (e *Encoding) DecodeUintFixed(s string, width int) (uint64, error) {
+	if width <= 0 || len(s) != width {
+		return 0, ErrWidth
+	}
+
+	return e.decodeUint64(s)
+}
+
+// Compare orders two encoded values by their symbol values. The alphabets are
+// not in ASCII order, so fixed width codes must be compared with Compare rather
+// than byte-wise to sort numerically.
+func (e *Encoding) Compare(a, b string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if da, db := e.decodeMap[a[i]], e.decodeMap[b[i]]; da != db {
+			if da < db {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+
+	return 0
+}
+
+// EncodeBigInt panics if x is negative.
+func (e *Encoding) EncodeBigInt(x *big.Int) string {
+	if x.Sign() < 0 {
+		panic("zrockford32: cannot encode a negative integer")
+	}
+
+	digits := []byte(x.Text(32))
+	for i, c := range digits {
+		if c <= '9' {
+			digits[i] = e.encoder[c-'0']
+		} else {
+			digits[i] = e.encoder[c-'a'+10]
+		}
+	}
+
+	return string(digits)
+}
+
+func (e *Encoding) DecodeBigInt(s string) (*big.Int, error) {
+	if len(s) == 0 || len(s) > 1 && e.decodeMap[s[0]] == 0 {
+		return nil, CorruptInputError(0)
+	}
+
+	digits := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		d := e.decodeMap[s[i]]
+		if d > 31 {
+			return nil, CorruptInputError(i)
+		}
+		digits[i] = bigDigits[d]
+	}
+
+	x, ok := new(big.Int).SetString(string(digits), 32)
+	if !ok {
+		return nil, CorruptInputError(0)
+	}
+
+	return x, nil
+}
@@ -0,0 +1,229 @@
+package zrockford32_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+type uintTestCase struct {
+	decoded uint64
+	encoded string
+}
+
+var uintTestsStd = []uintTestCase{
+	{0, "Y"},
+	{1, "B"},
+	{31, "9"},
+	{32, "BY"},
+	{1000, "9E"},
+	{123456789, "D2ZVE2"},
+	{math.MaxUint32, "D999999"},
+	{math.MaxUint64, "X999999999999"},
+}
+
+var uintTestsLwr = []uintTestCase{
+	{0, "y"},
+	{1, "b"},
+	{1000, "9e"},
+	{math.MaxUint64, "x999999999999"},
+}
+
+func TestEncodeUint64Std(t *testing.T) {
+	for _, tc := range uintTestsStd {
+		if g, e := zrockford32.StdEncoding.EncodeUint64(tc.decoded), tc.encoded; g != e {
+			t.Errorf("EncodeUint64 %d wrong result: %q != %q", tc.decoded, g, e)
+		}
+	}
+}
+
+func TestEncodeUint64Lwr(t *testing.T) {
+	for _, tc := range uintTestsLwr {
+		if g, e := zrockford32.LwrEncoding.EncodeUint64(tc.decoded), tc.encoded; g != e {
+			t.Errorf("EncodeUint64 %d wrong result: %q != %q", tc.decoded, g, e)
+		}
+	}
+}
+
+func TestAppendUint64(t *testing.T) {
+	dst := []byte("order-")
+	dst = zrockford32.StdEncoding.AppendUint64(dst, 1000)
+	if g, e := string(dst), "order-9E"; g != e {
+		t.Errorf("AppendUint64 wrong result: %q != %q", g, e)
+	}
+}
+
+func TestDecodeUint64Std(t *testing.T) {
+	for _, tc := range uintTestsStd {
+		v, err := zrockford32.StdEncoding.DecodeUint64(tc.encoded)
+		if err != nil {
+			t.Errorf("DecodeUint64 %q: error: %v", tc.encoded, err)
+			continue
+		}
+		if g, e := v, tc.decoded; g != e {
+			t.Errorf("DecodeUint64 %q wrong result: %d != %d", tc.encoded, g, e)
+		}
+	}
+}
+
+func TestDecodeUint64LeadingZeros(t *testing.T) {
+	// Each value has a single variable width code, without leading zeros.
+	for _, s := range []string{"YYY9E", "YB", "YY"} {
+		if _, err := zrockford32.StdEncoding.DecodeUint64(s); err != zrockford32.CorruptInputError(0) {
+			t.Errorf("DecodeUint64 %q: wrong error: %v", s, err)
+		}
+		if _, err := zrockford32.StdEncoding.DecodeBigInt(s); err != zrockford32.CorruptInputError(0) {
+			t.Errorf("DecodeBigInt %q: wrong error: %v", s, err)
+		}
+	}
+
+	// Tolerant encodings read zeros of the other case as zeros as well.
+	tolerant := zrockford32.LwrEncoding.WithIgnoreCase()
+	for _, s := range []string{"Yb", "yB", "YY"} {
+		if _, err := tolerant.DecodeUint64(s); err != zrockford32.CorruptInputError(0) {
+			t.Errorf("DecodeUint64 %q: wrong error: %v", s, err)
+		}
+		if _, err := tolerant.DecodeBigInt(s); err != zrockford32.CorruptInputError(0) {
+			t.Errorf("DecodeBigInt %q: wrong error: %v", s, err)
+		}
+	}
+
+	v, err := zrockford32.StdEncoding.DecodeUintFixed("YYY9E", 5)
+	if err != nil {
+		t.Fatalf("DecodeUintFixed: error: %v", err)
+	}
+	if g, e := v, uint64(1000); g != e {
+		t.Errorf("DecodeUintFixed wrong result: %d != %d", g, e)
+	}
+}
+
+func TestDecodeUint64Overflow(t *testing.T) {
+	for _, s := range []string{"B9999999999999", "0YYYYYYYYYYYY"} {
+		if _, err := zrockford32.StdEncoding.DecodeUint64(s); err != zrockford32.ErrOverflow {
+			t.Errorf("DecodeUint64 %q: wrong error: %v", s, err)
+		}
+	}
+}
+
+func TestDecodeUint64Bad(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		offset int64
+	}{
+		{"", 0},
+		{"9E!", 2},
+		{"9e", 1},
+	} {
+		_, err := zrockford32.StdEncoding.DecodeUint64(tc.input)
+		switch err := err.(type) {
+		case zrockford32.CorruptInputError:
+			if g, e := int64(err), tc.offset; g != e {
+				t.Errorf("DecodeUint64 %q: wrong offset: %d != %d", tc.input, g, e)
+			}
+		default:
+			t.Errorf("DecodeUint64 %q: wrong error: %T: %v", tc.input, err, err)
+		}
+	}
+}
+
+func TestBigIntRoundTrip(t *testing.T) {
+	for _, tc := range uintTestsStd {
+		x := new(big.Int).SetUint64(tc.decoded)
+		if g, e := zrockford32.StdEncoding.EncodeBigInt(x), tc.encoded; g != e {
+			t.Errorf("EncodeBigInt %d wrong result: %q != %q", tc.decoded, g, e)
+		}
+	}
+
+	x, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+	s := zrockford32.StdEncoding.EncodeBigInt(x)
+	if g, e := s, "89999999999999999999999999"; g != e {
+		t.Errorf("EncodeBigInt wrong result: %q != %q", g, e)
+	}
+	y, err := zrockford32.StdEncoding.DecodeBigInt(s)
+	if err != nil {
+		t.Fatalf("DecodeBigInt %q: error: %v", s, err)
+	}
+	if x.Cmp(y) != 0 {
+		t.Errorf("DecodeBigInt %q wrong result: %v != %v", s, y, x)
+	}
+}
+
+func TestDecodeBigIntBad(t *testing.T) {
+	_, err := zrockford32.StdEncoding.DecodeBigInt("9E!")
+	if err, ok := err.(zrockford32.CorruptInputError); !ok || err != 2 {
+		t.Errorf("DecodeBigInt: wrong error: %T: %v", err, err)
+	}
+}
+
+func TestEncodeUintFixed(t *testing.T) {
+	for _, tc := range []struct {
+		decoded uint64
+		width   int
+		encoded string
+	}{
+		{0, 1, "Y"},
+		{0, 4, "YYYY"},
+		{1000, 2, "9E"},
+		{1000, 6, "YYYY9E"},
+		{math.MaxUint64, 13, "X999999999999"},
+		{math.MaxUint64, 15, "YYX999999999999"},
+	} {
+		s, err := zrockford32.StdEncoding.EncodeUintFixed(tc.decoded, tc.width)
+		if err != nil {
+			t.Errorf("EncodeUintFixed %d width %d: error: %v", tc.decoded, tc.width, err)
+			continue
+		}
+		if g, e := s, tc.encoded; g != e {
+			t.Errorf("EncodeUintFixed %d width %d wrong result: %q != %q", tc.decoded, tc.width, g, e)
+		}
+
+		v, err := zrockford32.StdEncoding.DecodeUintFixed(s, tc.width)
+		if err != nil {
+			t.Errorf("DecodeUintFixed %q width %d: error: %v", s, tc.width, err)
+			continue
+		}
+		if g, e := v, tc.decoded; g != e {
+			t.Errorf("DecodeUintFixed %q width %d wrong result: %d != %d", s, tc.width, g, e)
+		}
+	}
+}
+
+func TestEncodeUintFixedTooWide(t *testing.T) {
+	for _, width := range []int{-1, 0, 1} {
+		if _, err := zrockford32.StdEncoding.EncodeUintFixed(1000, width); err != zrockford32.ErrWidth {
+			t.Errorf("EncodeUintFixed width %d: wrong error: %v", width, err)
+		}
+	}
+
+	dst, err := zrockford32.StdEncoding.AppendUintFixed([]byte("N-"), 1000, 1)
+	if err != zrockford32.ErrWidth {
+		t.Errorf("AppendUintFixed: wrong error: %v", err)
+	}
+	if g, e := string(dst), "N-"; g != e {
+		t.Errorf("AppendUintFixed modified dst on error: %q != %q", g, e)
+	}
+}
+
+func TestDecodeUintFixedWidth(t *testing.T) {
+	for _, s := range []string{"9E", "YYYYY9E"} {
+		if _, err := zrockford32.StdEncoding.DecodeUintFixed(s, 6); err != zrockford32.ErrWidth {
+			t.Errorf("DecodeUintFixed %q: wrong error: %v", s, err)
+		}
+	}
+}
+
+func TestEncodeUintFixedSorts(t *testing.T) {
+	var last string
+	for v := uint64(0); v < 5000; v += 7 {
+		s, err := zrockford32.StdEncoding.EncodeUintFixed(v, 4)
+		if err != nil {
+			t.Fatalf("EncodeUintFixed %d: error: %v", v, err)
+		}
+		if last != "" && zrockford32.StdEncoding.Compare(last, s) >= 0 {
+			t.Fatalf("EncodeUintFixed %d: %q does not sort after %q", v, s, last)
+		}
+		last = s
+	}
+}
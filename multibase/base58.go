@@ -0,0 +1,86 @@
+package multibase
+
+import "github.com/checksum0/go-zrockford32"
+
+// base58btc is the alphabet of Bitcoin addresses, without 0, I, O and l.
+const base58btc = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58DecodeMap = func() (m [256]byte) {
+	for i := range m {
+		m[i] = 0xff
+	}
+	for i := 0; i < len(base58btc); i++ {
+		m[base58btc[i]] = byte(i)
+	}
+
+	return m
+}()
+
+// base58Encode encodes src as a big-endian number in base 58, each leading
+// zero byte as a leading 1.
+func base58Encode(src []byte) string {
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) is less than 1.37.
+	digits := make([]byte, 0, (len(src)-zeros)*137/100+1)
+	for _, b := range src[zeros:] {
+		carry := int(b)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	dst := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		dst[i] = base58btc[0]
+	}
+	for i, d := range digits {
+		dst[len(dst)-1-i] = base58btc[d]
+	}
+
+	return string(dst)
+}
+
+// base58Decode is the inverse of base58Encode.
+func base58Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58btc[0] {
+		zeros++
+	}
+
+	// log(58) / log(256) is less than 0.74.
+	digits := make([]byte, 0, (len(s)-zeros)*74/100+1)
+	for i := zeros; i < len(s); i++ {
+		d := base58DecodeMap[s[i]]
+		if d == 0xff {
+			return nil, zrockford32.CorruptInputError(i)
+		}
+
+		carry := int(d)
+		for j := range digits {
+			carry += int(digits[j]) * 58
+			digits[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	dst := make([]byte, zeros+len(digits))
+	for i, b := range digits {
+		dst[len(dst)-1-i] = b
+	}
+
+	return dst, nil
+}
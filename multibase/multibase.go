@@ -0,0 +1,198 @@
+// Package multibase prefixes encoded data with the character naming its base,
+// as specified by multibase, so that decoders need not be told which base was
+// used.
+//
+// The alphabets of this project are not part of the specification, which only
+// knows z-base-32, "h", whose alphabet differs from the lowercase one of this
+// project in three symbols. They are given prefixes of their own instead, "H"
+// for the standard encoding and "r" for the lowercase one, which other
+// multibase implementations will not recognise. Decode also accepts the
+// hexadecimal, base32, z-base-32, base58btc and base64 bases of the
+// specification. The other bases of the specification, base2, base8, base10,
+// base36, base45, base58flickr, proquint and the identity, are not supported,
+// but their prefixes cannot be registered either.
+//
+// The prefixes of this project are resolved through the encoding registry, so
+// that encodings registered there can be given a prefix with Register.
+package multibase
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+var (
+	ErrPrefix        = errors.New("unknown multibase prefix")
+	ErrNotRegistered = errors.New("encoding has no multibase prefix")
+)
+
+const (
+	ZBase32        = 'h'
+	Base58BTC      = 'z'
+	ZRockford      = 'H'
+	ZRockfordLower = 'r'
+)
+
+// zBase32 is the z-base-32 encoding of the specification, which packs bits as
+// this project does, with another alphabet.
+var zBase32 = zrockford32.NewEncoding("ybndrfg8ejkmcpqxot1uwisza345h769")
+
+// base is one of the standard bases of the specification.
+type base struct {
+	encode func([]byte) string
+	decode func(string) ([]byte, error)
+}
+
+func base32Base(e *base32.Encoding, lower bool) base {
+	b := base{encode: e.EncodeToString, decode: e.DecodeString}
+	if lower {
+		b.encode = func(src []byte) string { return strings.ToLower(e.EncodeToString(src)) }
+		b.decode = func(s string) ([]byte, error) { return e.DecodeString(strings.ToUpper(s)) }
+	}
+
+	return b
+}
+
+func base64Base(e *base64.Encoding) base {
+	return base{encode: e.EncodeToString, decode: e.DecodeString}
+}
+
+var bases = map[byte]base{
+	'f': {encode: hex.EncodeToString, decode: hex.DecodeString},
+	'F': {encode: func(src []byte) string { return strings.ToUpper(hex.EncodeToString(src)) }, decode: hex.DecodeString},
+	'b': base32Base(base32.StdEncoding.WithPadding(base32.NoPadding), true),
+	'B': base32Base(base32.StdEncoding.WithPadding(base32.NoPadding), false),
+	'c': base32Base(base32.StdEncoding, true),
+	'C': base32Base(base32.StdEncoding, false),
+	'v': base32Base(base32.HexEncoding.WithPadding(base32.NoPadding), true),
+	'V': base32Base(base32.HexEncoding.WithPadding(base32.NoPadding), false),
+	't': base32Base(base32.HexEncoding, true),
+	'T': base32Base(base32.HexEncoding, false),
+	'm': base64Base(base64.RawStdEncoding),
+	'M': base64Base(base64.StdEncoding),
+	'u': base64Base(base64.RawURLEncoding),
+	'U': base64Base(base64.URLEncoding),
+	'h': {encode: zBase32.EncodeToString, decode: zBase32.DecodeString},
+	'z': {encode: base58Encode, decode: base58Decode},
+}
+
+// reserved holds the prefixes of the specification which are not in bases,
+// either because their base is not supported or because the specification
+// reserves them.
+const reserved = "079kKZpR1Q/"
+
+// registry maps prefixes to names of the zrockford32 encoding registry.
+var registry = struct {
+	sync.RWMutex
+	names map[byte]string
+}{
+	names: map[byte]string{
+		ZRockford:      "std",
+		ZRockfordLower: "lwr",
+	},
+}
+
+// Register gives prefix to the zrockford32 encoding registered as name. It
+// panics if prefix is used or reserved by the specification, or already
+// registered.
+func Register(prefix byte, name string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if prefix <= ' ' || prefix > '~' {
+		panic("multibase: prefix must be a printable ASCII character")
+	}
+	if _, ok := bases[prefix]; ok || strings.IndexByte(reserved, prefix) >= 0 {
+		panic("multibase: prefix " + string(rune(prefix)) + " is used by a standard base")
+	}
+	if _, ok := registry.names[prefix]; ok {
+		panic("multibase: prefix " + string(rune(prefix)) + " is already registered")
+	}
+
+	registry.names[prefix] = name
+}
+
+func lookup(prefix byte) (*zrockford32.Encoding, bool) {
+	registry.RLock()
+	name, ok := registry.names[prefix]
+	registry.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return zrockford32.LookupEncoding(name)
+}
+
+// Prefix returns the prefix of e, which must have been registered in the
+// encoding registry and given a prefix.
+func Prefix(e *zrockford32.Encoding) (byte, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for prefix, name := range registry.names {
+		if registered, ok := zrockford32.LookupEncoding(name); ok && registered == e {
+			return prefix, true
+		}
+	}
+
+	return 0, false
+}
+
+// Encode returns data encoded with e, prefixed with its multibase prefix.
+func Encode(e *zrockford32.Encoding, data []byte) (string, error) {
+	prefix, ok := Prefix(e)
+	if !ok {
+		return "", ErrNotRegistered
+	}
+
+	return string(rune(prefix)) + e.EncodeToString(data), nil
+}
+
+// EncodeBase returns data encoded with the base named by prefix, either one of
+// the standard bases or a registered zrockford32 encoding.
+func EncodeBase(prefix byte, data []byte) (string, error) {
+	if b, ok := bases[prefix]; ok {
+		return string(rune(prefix)) + b.encode(data), nil
+	}
+	if e, ok := lookup(prefix); ok {
+		return string(rune(prefix)) + e.EncodeToString(data), nil
+	}
+
+	return "", ErrPrefix
+}
+
+// Decode decodes s with the base named by its prefix.
+func Decode(s string) ([]byte, error) {
+	if len(s) == 0 {
+		return nil, ErrPrefix
+	}
+
+	var data []byte
+	var err error
+	if b, ok := bases[s[0]]; ok {
+		data, err = b.decode(s[1:])
+	} else if e, ok := lookup(s[0]); ok {
+		data, err = e.DecodeString(s[1:])
+	} else {
+		return nil, ErrPrefix
+	}
+
+	// Report corrupt input at its offset in s, prefix included.
+	switch offset := err.(type) {
+	case zrockford32.CorruptInputError:
+		err = offset + 1
+	case base32.CorruptInputError:
+		err = offset + 1
+	case base64.CorruptInputError:
+		err = offset + 1
+	}
+
+	return data, err
+}
@@ -0,0 +1,194 @@
+package multibase_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+	"github.com/checksum0/go-zrockford32/multibase"
+)
+
+func TestSpecVectors(t *testing.T) {
+	// Vectors of the multibase specification for "yes mani !".
+	data := []byte("yes mani !")
+
+	for _, tc := range []struct {
+		prefix byte
+		code   string
+	}{
+		{'f', "f796573206d616e692021"},
+		{'F', "F796573206D616E692021"},
+		{'b', "bpfsxgidnmfxgsibb"},
+		{'B', "BPFSXGIDNMFXGSIBB"},
+		{'v', "vf5in683dc5n6i811"},
+		{'V', "VF5IN683DC5N6I811"},
+		{'c', "cpfsxgidnmfxgsibb"},
+		{'t', "tf5in683dc5n6i811"},
+		{'h', "hxf1zgedpcfzg1ebb"},
+		{'z', "z7paNL19xttacUY"},
+		{'m', "meWVzIG1hbmkgIQ"},
+		{'M', "MeWVzIG1hbmkgIQ=="},
+		{'u', "ueWVzIG1hbmkgIQ"},
+		{'U', "UeWVzIG1hbmkgIQ=="},
+	} {
+		code, err := multibase.EncodeBase(tc.prefix, data)
+		if err != nil {
+			t.Errorf("EncodeBase %c: error: %v", tc.prefix, err)
+		} else if g, e := code, tc.code; g != e {
+			t.Errorf("EncodeBase %c wrong result: %q != %q", tc.prefix, g, e)
+		}
+
+		decoded, err := multibase.Decode(tc.code)
+		if err != nil {
+			t.Errorf("Decode %q: error: %v", tc.code, err)
+		} else if g, e := decoded, data; !bytes.Equal(g, e) {
+			t.Errorf("Decode %q wrong result: %q != %q", tc.code, g, e)
+		}
+	}
+}
+
+func TestLeadingZeros(t *testing.T) {
+	for _, tc := range []struct {
+		prefix byte
+		data   []byte
+		code   string
+	}{
+		// From the leading zeros vectors of the specification.
+		{multibase.ZBase32, []byte("\x00\x00yes mani !"), "hyyy813murbssn5ujryoo"},
+		{multibase.ZBase32, []byte{0x80}, "hoy"},
+		{multibase.Base58BTC, []byte("\x00yes mani !"), "z17paNL19xttacUY"},
+		{multibase.Base58BTC, []byte("\x00\x00yes mani !"), "z117paNL19xttacUY"},
+		{multibase.Base58BTC, []byte{0, 0}, "z11"},
+		{multibase.Base58BTC, []byte{}, "z"},
+	} {
+		code, err := multibase.EncodeBase(tc.prefix, tc.data)
+		if err != nil {
+			t.Errorf("EncodeBase %c %q: error: %v", tc.prefix, tc.data, err)
+		} else if g, e := code, tc.code; g != e {
+			t.Errorf("EncodeBase %c %q wrong result: %q != %q", tc.prefix, tc.data, g, e)
+		}
+
+		decoded, err := multibase.Decode(tc.code)
+		if err != nil {
+			t.Errorf("Decode %q: error: %v", tc.code, err)
+		} else if g, e := decoded, tc.data; !bytes.Equal(g, e) {
+			t.Errorf("Decode %q wrong result: %q != %q", tc.code, g, e)
+		}
+	}
+}
+
+func TestZBase32(t *testing.T) {
+	// z-base-32 uses o, u and i where the lowercase encoding of this project
+	// uses 0, v and 2.
+	code, err := multibase.Encode(zrockford32.LwrEncoding, []byte{0x80})
+	if err != nil {
+		t.Fatalf("Encode: error: %v", err)
+	}
+	if g, e := code, "r0y"; g != e {
+		t.Errorf("Encode wrong result: %q != %q", g, e)
+	}
+	if _, err := multibase.Decode("h0y"); err != zrockford32.CorruptInputError(1) {
+		t.Errorf("Decode %q: wrong error: %v", "h0y", err)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	data := []byte("hello, world\n")
+
+	for _, tc := range []struct {
+		encoding *zrockford32.Encoding
+		code     string
+	}{
+		{zrockford32.LwrEncoding, "rpb1sa5dxf008q551pt1yw"},
+		{zrockford32.StdEncoding, "HPB1SA5DXF008Q551PT1YW"},
+	} {
+		code, err := multibase.Encode(tc.encoding, data)
+		if err != nil {
+			t.Fatalf("Encode: error: %v", err)
+		}
+		if g, e := code, tc.code; g != e {
+			t.Errorf("Encode wrong result: %q != %q", g, e)
+		}
+
+		decoded, err := multibase.Decode(code)
+		if err != nil {
+			t.Fatalf("Decode %q: error: %v", code, err)
+		}
+		if g, e := decoded, data; !bytes.Equal(g, e) {
+			t.Errorf("Decode %q wrong result: %q != %q", code, g, e)
+		}
+	}
+
+	custom := zrockford32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ")
+	if _, err := multibase.Encode(custom, data); err != multibase.ErrNotRegistered {
+		t.Errorf("Encode unregistered encoding: wrong error: %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	custom := zrockford32.NewEncoding("0123456789abcdefghjkmnpqrstvwxyz")
+	zrockford32.RegisterEncoding("multibase-test", custom)
+	multibase.Register('X', "multibase-test")
+
+	prefix, ok := multibase.Prefix(custom)
+	if !ok {
+		t.Fatalf("Prefix: not registered")
+	}
+	if g, e := prefix, byte('X'); g != e {
+		t.Errorf("Prefix wrong result: %c != %c", g, e)
+	}
+
+	code, err := multibase.Encode(custom, []byte{0x34, 0x5a})
+	if err != nil {
+		t.Fatalf("Encode: error: %v", err)
+	}
+	if g, e := code, "X6hd0"; g != e {
+		t.Errorf("Encode wrong result: %q != %q", g, e)
+	}
+
+	decoded, err := multibase.Decode(code)
+	if err != nil {
+		t.Fatalf("Decode %q: error: %v", code, err)
+	}
+	if g, e := decoded, []byte{0x34, 0x5a}; !bytes.Equal(g, e) {
+		t.Errorf("Decode %q wrong result: %x != %x", code, g, e)
+	}
+
+	// Prefixes of the specification, whether supported or not, of this
+	// project and already registered, and unprintable ones.
+	for _, prefix := range []byte("bhzZkK079pRQ1/HrX \x00\x80") {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register %q: did not panic", prefix)
+				}
+			}()
+			multibase.Register(prefix, "std")
+		}()
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, s := range []string{"", "!abc", "?", "k2lcpzo5yikidynfl"} {
+		if _, err := multibase.Decode(s); err != multibase.ErrPrefix {
+			t.Errorf("Decode %q: wrong error: %v", s, err)
+		}
+	}
+
+	// Corrupt input is reported at its offset in the prefixed string.
+	for _, tc := range []struct {
+		code   string
+		offset int64
+	}{
+		{"HPB1!", 4},
+		// Uppercase is not z-base-32.
+		{"hPB1SA5DX", 1},
+		// 0, O, I and l are not in the base58btc alphabet.
+		{"z7paNL19xttacU0", 14},
+		{"z1O", 2},
+	} {
+		if _, err := multibase.Decode(tc.code); err != zrockford32.CorruptInputError(tc.offset) {
+			t.Errorf("Decode %q: wrong error: %v", tc.code, err)
+		}
+	}
+}
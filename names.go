@@ -0,0 +1,166 @@
+package zrockford32
+
+import (
+	"errors"
+	"strings"
+)
+
+// Names
+//
+// Codes end up in URLs, file names and DNS labels, each of which restricts the
+// characters, length or words it accepts. The standard alphabets only use
+// letters and digits, so their plain codes fit all three once they are short
+// enough and do not spell a reserved name.
+
+var ErrDNSLabel = errors.New("zrockford32 encoding cannot be used in DNS labels")
+var ErrDNSName = errors.New("zrockford32 data too long for a DNS name")
+
+const (
+	// MaxDNSLabelLen is the maximum length of a DNS label.
+	MaxDNSLabelLen = 63
+	// MaxDNSNameLen is the maximum length of a DNS name in text form,
+	// dots included.
+	MaxDNSNameLen = 253
+)
+
+// reservedNames are the device names which Windows does not allow as file
+// names, whatever their case and extension.
+var reservedNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM0", "COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT0", "LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+func isAlnum(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// IsDNSLabel reports whether s is a valid DNS label: 1 to 63 letters, digits
+// and hyphens, neither starting nor ending with a hyphen.
+func IsDNSLabel(s string) bool {
+	if len(s) == 0 || len(s) > MaxDNSLabelLen || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isAlnum(s[i]) && s[i] != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsURLSafe reports whether s only contains the characters which RFC 3986
+// leaves unreserved, and so can be put in any part of a URL without escaping.
+func IsURLSafe(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isAlnum(s[i]) && !strings.ContainsRune("-._~", rune(s[i])) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsReservedName reports whether name is a device name reserved by Windows,
+// such as CON or NUL. The case, the extension and trailing spaces do not
+// matter: "nul.txt" is reserved as well.
+func IsReservedName(name string) bool {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimRight(name, " ")
+
+	for _, reserved := range reservedNames {
+		if strings.EqualFold(name, reserved) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsFilenameSafe reports whether name can be used as a file name on Windows
+// and Unix systems alike: it is not empty, not "." or "..", not reserved, has
+// no path separator, control or other forbidden character, and does not end
+// with a dot or a space.
+func IsFilenameSafe(name string) bool {
+	if len(name) == 0 || name == "." || name == ".." || IsReservedName(name) {
+		return false
+	}
+	if last := name[len(name)-1]; last == '.' || last == ' ' {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		if name[i] < ' ' || name[i] == 0x7F || strings.ContainsRune(`/\:*?"<>|`, rune(name[i])) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsCaseFoldSafe reports whether no two symbols of the alphabet are the same
+// letter in different cases, so that distinct codes remain distinct on case
+// insensitive file systems and in DNS names.
+func (e *Encoding) IsCaseFoldSafe() bool {
+	for i := 0; i < len(e.encoder); i++ {
+		for j := i + 1; j < len(e.encoder); j++ {
+			if strings.EqualFold(e.encoder[i:i+1], e.encoder[j:j+1]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// isDNSSafe reports whether codes of e can be put in DNS labels as they are.
+func (e *Encoding) isDNSSafe() bool {
+	for i := 0; i < len(e.encoder); i++ {
+		if !isAlnum(e.encoder[i]) {
+			return false
+		}
+	}
+
+	return e.IsCaseFoldSafe()
+}
+
+// EncodeDNSLabels encodes data and splits the code into labels of at most 63
+// symbols, to be joined with dots under a domain for DNS-based transport. The
+// alphabet must only contain letters and digits and be safe from case folding,
+// since resolvers do not preserve case. ErrDNSName is returned if the labels
+// joined with dots exceed MaxDNSNameLen; callers appending a domain must check
+// that the whole name still fits.
+func (e *Encoding) EncodeDNSLabels(data []byte) ([]string, error) {
+	if !e.isDNSSafe() {
+		return nil, ErrDNSLabel
+	}
+
+	code := e.EncodeToString(data)
+	n := (len(code) + MaxDNSLabelLen - 1) / MaxDNSLabelLen
+	if len(code)+n-1 > MaxDNSNameLen {
+		return nil, ErrDNSName
+	}
+
+	labels := make([]string, 0, n)
+	for len(code) > 0 {
+		n := min(len(code), MaxDNSLabelLen)
+		labels = append(labels, code[:n])
+		code = code[n:]
+	}
+
+	return labels, nil
+}
+
+// DecodeDNSLabels decodes data split into labels by EncodeDNSLabels, in either
+// case.
+func (e *Encoding) DecodeDNSLabels(labels []string) ([]byte, error) {
+	if !e.isDNSSafe() {
+		return nil, ErrDNSLabel
+	}
+
+	return e.WithIgnoreCase().DecodeString(strings.Join(labels, ""))
+}
@@ -0,0 +1,180 @@
+package zrockford32_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func TestIsDNSLabel(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		match bool
+	}{
+		{"PB1SA5DX", true},
+		{"pb1s-a5dx", true},
+		{"", false},
+		{"-pb1s", false},
+		{"pb1s-", false},
+		{"pb1s_a5dx", false},
+		{"pb1s.a5dx", false},
+		{strings.Repeat("y", 63), true},
+		{strings.Repeat("y", 64), false},
+		{"inv_PB1SA5DX", false},
+		{"PB1SA5DXF008Q551PT1YWHRG", true},
+	} {
+		if g, e := zrockford32.IsDNSLabel(tc.name), tc.match; g != e {
+			t.Errorf("IsDNSLabel %q wrong result: %v != %v", tc.name, g, e)
+		}
+	}
+}
+
+func TestIsURLSafe(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		match bool
+	}{
+		{"", true},
+		{"PB1S-A5DX", true},
+		{"inv_PB1SA5DX.txt", true},
+		{"~pb1s", true},
+		{"PB1S A5DX", false},
+		{"PB1S/A5DX", false},
+		{"PB1S+A5DX", false},
+	} {
+		if g, e := zrockford32.IsURLSafe(tc.name), tc.match; g != e {
+			t.Errorf("IsURLSafe %q wrong result: %v != %v", tc.name, g, e)
+		}
+	}
+}
+
+func TestIsReservedName(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		match bool
+	}{
+		{"PRN", true},
+		{"prn", true},
+		{"Nul.txt", true},
+		{"COM1", true},
+		{"lpt9.tar.gz", true},
+		{"AUX ", true},
+		{"PRNB", false},
+		{"COM", false},
+		{"PB1SA5DX", false},
+		{"", false},
+	} {
+		if g, e := zrockford32.IsReservedName(tc.name), tc.match; g != e {
+			t.Errorf("IsReservedName %q wrong result: %v != %v", tc.name, g, e)
+		}
+	}
+}
+
+func TestIsFilenameSafe(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		match bool
+	}{
+		{"PB1SA5DX", true},
+		{"pb1s-a5dx.z32", true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"prn.txt", false},
+		{"PB1S/A5DX", false},
+		{"PB1S:A5DX", false},
+		{"PB1SA5DX.", false},
+		{"PB1SA5DX ", false},
+		{"PB1S\tA5DX", false},
+	} {
+		if g, e := zrockford32.IsFilenameSafe(tc.name), tc.match; g != e {
+			t.Errorf("IsFilenameSafe %q wrong result: %v != %v", tc.name, g, e)
+		}
+	}
+}
+
+func TestIsCaseFoldSafe(t *testing.T) {
+	for _, tc := range []struct {
+		encoding *zrockford32.Encoding
+		match    bool
+	}{
+		{zrockford32.StdEncoding, true},
+		{zrockford32.LwrEncoding, true},
+		{zrockford32.NewEncoding("abcdefghijklmnopqrstuvwxyzABCDEF"), false},
+	} {
+		if g, e := tc.encoding.IsCaseFoldSafe(), tc.match; g != e {
+			t.Errorf("IsCaseFoldSafe wrong result: %v != %v", g, e)
+		}
+	}
+}
+
+func TestDNSLabels(t *testing.T) {
+	data := bytes.Repeat([]byte("hello, world\n"), 10)
+
+	labels, err := zrockford32.LwrEncoding.EncodeDNSLabels(data)
+	if err != nil {
+		t.Fatalf("EncodeDNSLabels: error: %v", err)
+	}
+	if g, e := len(labels), 4; g != e {
+		t.Fatalf("EncodeDNSLabels labels wrong result: %d != %d", g, e)
+	}
+	for _, label := range labels {
+		if !zrockford32.IsDNSLabel(label) {
+			t.Errorf("EncodeDNSLabels wrong label: %q", label)
+		}
+	}
+	if g, e := strings.Join(labels, ""), zrockford32.LwrEncoding.EncodeToString(data); g != e {
+		t.Errorf("EncodeDNSLabels wrong result: %q != %q", g, e)
+	}
+
+	// Resolvers may change the case of names.
+	labels[1] = strings.ToUpper(labels[1])
+	decoded, err := zrockford32.LwrEncoding.DecodeDNSLabels(labels)
+	if err != nil {
+		t.Fatalf("DecodeDNSLabels: error: %v", err)
+	}
+	if g, e := decoded, data; !bytes.Equal(g, e) {
+		t.Errorf("DecodeDNSLabels wrong result: %q != %q", g, e)
+	}
+
+	// 156 bytes make 250 symbols, in 4 labels joined by 3 dots: 253
+	// characters, the longest name.
+	if _, err := zrockford32.LwrEncoding.EncodeDNSLabels(make([]byte, 156)); err != nil {
+		t.Errorf("EncodeDNSLabels 156 bytes: error: %v", err)
+	}
+	if _, err := zrockford32.LwrEncoding.EncodeDNSLabels(make([]byte, 157)); err != zrockford32.ErrDNSName {
+		t.Errorf("EncodeDNSLabels 157 bytes: wrong error: %v", err)
+	}
+
+	mixed := zrockford32.NewEncoding("abcdefghijklmnopqrstuvwxyzABCDEF")
+	if _, err := mixed.EncodeDNSLabels(data); err != zrockford32.ErrDNSLabel {
+		t.Errorf("EncodeDNSLabels mixed case alphabet: wrong error: %v", err)
+	}
+	symbols := zrockford32.NewEncoding("abcdefghijklmnopqrstuvwxyz-_.~+/")
+	if _, err := symbols.DecodeDNSLabels(labels); err != zrockford32.ErrDNSLabel {
+		t.Errorf("DecodeDNSLabels symbols in alphabet: wrong error: %v", err)
+	}
+}
+
+func TestGenerateCodeAccept(t *testing.T) {
+	// 0x69 0x04 encodes to PRN, a reserved name, and the zeros to YYY.
+	reader := bytes.NewReader([]byte{0x69, 0x04, 0x00, 0x00})
+
+	code, err := zrockford32.GenerateCode(15,
+		zrockford32.WithRand(reader),
+		zrockford32.WithAccept(zrockford32.IsFilenameSafe),
+	)
+	if err != nil {
+		t.Fatalf("GenerateCode: error: %v", err)
+	}
+	if g, e := code.Code, "YYY"; g != e {
+		t.Errorf("GenerateCode wrong result: %q != %q", g, e)
+	}
+
+	reject := func(string) bool { return false }
+	if _, err := zrockford32.GenerateCode(80, zrockford32.WithAccept(reject)); err == nil {
+		t.Errorf("GenerateCode rejecting every code succeeded")
+	}
+}
@@ -0,0 +1,183 @@
+package zrockford32
+
+import (
+	"errors"
+	"strings"
+)
+
+// Prefixed codes
+//
+// A prefixed code is made of a human-readable prefix naming the kind of code,
+// an underscore, the encoded data and a six symbol checksum, in the manner of
+// Bech32. The checksum covers the prefix as well, so a code of one kind is
+// rejected when read as another, and it detects any error affecting up to four
+// symbols of codes of up to 89 characters.
+
+var ErrPrefix = errors.New("zrockford32 missing or invalid human-readable prefix")
+var ErrChecksum = errors.New("zrockford32 checksum mismatch")
+
+const (
+	PrefixSeparator = '_'
+
+	prefixChecksumLen = 6
+	prefixMaxLen      = 83
+
+	// prefixConstant is the final value of the checksum, the one of Bech32m,
+	// which unlike Bech32 is not weakened by symbols inserted before the end.
+	prefixConstant = 0x2bc830a3
+)
+
+var prefixGenerator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// ValidatePrefix reports whether hrp can be used as the prefix of a code: it
+// must be 1 to 83 printable ASCII characters other than space and underscore.
+func ValidatePrefix(hrp string) error {
+	if len(hrp) == 0 || len(hrp) > prefixMaxLen {
+		return ErrPrefix
+	}
+
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] <= ' ' || hrp[i] > '~' || hrp[i] == PrefixSeparator {
+			return ErrPrefix
+		}
+	}
+
+	return nil
+}
+
+func (e *Encoding) EncodePrefixed(hrp string, data []byte) (string, error) {
+	if err := ValidatePrefix(hrp); err != nil {
+		return "", err
+	}
+
+	encoded := e.EncodeToString(data)
+	symbols := make([]byte, len(encoded), len(encoded)+prefixChecksumLen)
+	for i := 0; i < len(encoded); i++ {
+		symbols[i] = e.decodeMap[encoded[i]]
+	}
+
+	checksum := prefixPolymod(hrp, append(symbols, make([]byte, prefixChecksumLen)...)) ^ prefixConstant
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(encoded) + prefixChecksumLen)
+	b.WriteString(hrp)
+	b.WriteByte(PrefixSeparator)
+	b.WriteString(encoded)
+	for i := 0; i < prefixChecksumLen; i++ {
+		b.WriteByte(e.encoder[checksum>>uint(5*(prefixChecksumLen-1-i))&31])
+	}
+
+	return b.String(), nil
+}
+
+// DecodePrefixed splits s at its first underscore, as prefixes never contain
+// one, and returns its prefix and the data it holds once its checksum has been
+// verified. Callers must check that the prefix is the expected one.
+func (e *Encoding) DecodePrefixed(s string) (string, []byte, error) {
+	sep := strings.IndexByte(s, PrefixSeparator)
+	if sep < 0 {
+		return "", nil, ErrPrefix
+	}
+
+	hrp := s[:sep]
+	if err := ValidatePrefix(hrp); err != nil {
+		return "", nil, err
+	}
+
+	symbols := make([]byte, 0, len(s)-sep-1)
+	offsets := make([]int, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		switch d := e.decodeMap[s[i]]; {
+		case d <= 31:
+			symbols = append(symbols, d)
+			offsets = append(offsets, i)
+		case d != ignoredSymbol:
+			return "", nil, CorruptInputError(i)
+		}
+	}
+
+	if len(symbols) < prefixChecksumLen || prefixPolymod(hrp, symbols) != prefixConstant {
+		return "", nil, ErrChecksum
+	}
+
+	encoded := make([]byte, len(symbols)-prefixChecksumLen)
+	for i := range encoded {
+		encoded[i] = e.encoder[symbols[i]]
+	}
+
+	// Only the canonical encoding of the data is accepted, so that a given
+	// prefix and data always make the same code.
+	data, err := e.decodeString(string(encoded), -1)
+	if err != nil {
+		return "", nil, err
+	}
+	if e.EncodeToString(data) != string(encoded) {
+		return "", nil, CorruptInputError(offsets[len(encoded)-1])
+	}
+
+	return hrp, data, nil
+}
+
+// EncodePrefixedGrouped is like EncodePrefixed, but splits the code after the
+// prefix in groups of size symbols separated by sep, or not at all if size is
+// 0.
+func (e *Encoding) EncodePrefixedGrouped(hrp string, data []byte, size int, sep string) (string, error) {
+	code, err := e.EncodePrefixed(hrp, data)
+	if err != nil {
+		return "", err
+	}
+
+	return code[:len(hrp)+1] + Group(code[len(hrp)+1:], size, sep), nil
+}
+
+// DecodePrefixedTolerant decodes codes as people type them back: surrounding
+// spaces are trimmed, the prefix and the symbols are accepted in either case,
+// and sep and spaces between symbols are skipped. It returns ErrPrefix unless
+// the prefix of s is hrp, though codes of another kind usually fail their
+// checksum first. It panics if sep overlaps the alphabet.
+func (e *Encoding) DecodePrefixedTolerant(s, hrp, sep string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+
+	// The checksum covers the prefix as encoded, in the case of hrp.
+	if i := strings.IndexByte(s, PrefixSeparator); i >= 0 && strings.EqualFold(s[:i], hrp) {
+		s = hrp + s[i:]
+	}
+
+	prefix, data, err := e.WithIgnoreCase().WithIgnoredChars(sep + " ").DecodePrefixed(s)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != hrp {
+		return nil, ErrPrefix
+	}
+
+	return data, nil
+}
+
+// prefixPolymod computes the Bech32 checksum of the prefix, expanded into the
+// high and low bits of its characters, followed by the symbols.
+func prefixPolymod(hrp string, symbols []byte) uint32 {
+	chk := uint32(1)
+	step := func(v byte) {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range prefixGenerator {
+			if top>>uint(i)&1 != 0 {
+				chk ^= g
+			}
+		}
+	}
+
+	for i := 0; i < len(hrp); i++ {
+		step(hrp[i] >> 5)
+	}
+	step(0)
+	for i := 0; i < len(hrp); i++ {
+		step(hrp[i] & 31)
+	}
+	for _, v := range symbols {
+		step(v)
+	}
+
+	return chk
+}
@@ -0,0 +1,147 @@
+package zrockford32_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+var prefixedTests = []struct {
+	hrp     string
+	decoded []byte
+	encoded string
+}{
+	{"inv", []byte{}, "inv_ZNKCX7"},
+	{"inv", []byte("hello"), "inv_PB1SA5DX0DVHF4"},
+	{"ord", []byte{0, 1, 2, 3}, "ord_YYY0RYAYCJ395"},
+	{"key", []byte("hello, world"), "key_PB1SA5DXF008Q551PT1YWHRGRQ"},
+}
+
+func TestEncodePrefixed(t *testing.T) {
+	for _, tc := range prefixedTests {
+		s, err := zrockford32.StdEncoding.EncodePrefixed(tc.hrp, tc.decoded)
+		if err != nil {
+			t.Errorf("EncodePrefixed %q %q: error: %v", tc.hrp, tc.decoded, err)
+			continue
+		}
+		if g, e := s, tc.encoded; g != e {
+			t.Errorf("EncodePrefixed %q %q wrong result: %q != %q", tc.hrp, tc.decoded, g, e)
+		}
+	}
+}
+
+func TestDecodePrefixed(t *testing.T) {
+	for _, tc := range prefixedTests {
+		hrp, data, err := zrockford32.StdEncoding.DecodePrefixed(tc.encoded)
+		if err != nil {
+			t.Errorf("DecodePrefixed %q: error: %v", tc.encoded, err)
+			continue
+		}
+		if hrp != tc.hrp || !bytes.Equal(data, tc.decoded) {
+			t.Errorf("DecodePrefixed %q wrong result: %q %q != %q %q", tc.encoded, hrp, data, tc.hrp, tc.decoded)
+		}
+	}
+}
+
+func TestDecodePrefixedDetectsErrors(t *testing.T) {
+	const alphabet = "YBNDRFG8EJKMCPQX0T1VW2SZA345H769"
+	code := "key_PB1SA5DXF008Q551PT1YWHRGRQ"
+
+	for i := 4; i < len(code); i++ {
+		for j := 0; j < len(alphabet); j++ {
+			if alphabet[j] == code[i] {
+				continue
+			}
+			typo := code[:i] + string(alphabet[j]) + code[i+1:]
+			if _, _, err := zrockford32.StdEncoding.DecodePrefixed(typo); err != zrockford32.ErrChecksum {
+				t.Fatalf("DecodePrefixed %q: wrong error: %v", typo, err)
+			}
+		}
+	}
+
+	for i := 4; i+1 < len(code); i++ {
+		if code[i] == code[i+1] {
+			continue
+		}
+		typo := code[:i] + code[i+1:i+2] + code[i:i+1] + code[i+2:]
+		if _, _, err := zrockford32.StdEncoding.DecodePrefixed(typo); err != zrockford32.ErrChecksum {
+			t.Fatalf("DecodePrefixed %q: wrong error: %v", typo, err)
+		}
+	}
+}
+
+func TestDecodePrefixedWrongKind(t *testing.T) {
+	for _, s := range []string{"ord_PB1SA5DX0DVHF4", "Inv_PB1SA5DX0DVHF4", "in_PB1SA5DX0DVHF4"} {
+		if _, _, err := zrockford32.StdEncoding.DecodePrefixed(s); err != zrockford32.ErrChecksum {
+			t.Errorf("DecodePrefixed %q: wrong error: %v", s, err)
+		}
+	}
+}
+
+func TestDecodePrefixedTolerant(t *testing.T) {
+	encoding := zrockford32.StdEncoding.WithIgnoreCase().WithIgnoredChars("-")
+
+	hrp, data, err := encoding.DecodePrefixed("inv_pb1s-a5dx-0dvh-f4")
+	if err != nil {
+		t.Fatalf("DecodePrefixed: error: %v", err)
+	}
+	if hrp != "inv" || string(data) != "hello" {
+		t.Errorf("DecodePrefixed wrong result: %q %q", hrp, data)
+	}
+}
+
+func TestPrefixedGrouped(t *testing.T) {
+	code, err := zrockford32.StdEncoding.EncodePrefixedGrouped("key", []byte("hello, world"), 4, "-")
+	if err != nil {
+		t.Fatalf("EncodePrefixedGrouped: error: %v", err)
+	}
+	if g, e := code, "key_PB1S-A5DX-F008-Q551-PT1Y-WHRG-RQ"; g != e {
+		t.Errorf("EncodePrefixedGrouped wrong result: %q != %q", g, e)
+	}
+
+	for _, s := range []string{code, " KEY_pb1s-a5dx-f008 q551-pt1y-whrg-rq\n", "key_PB1SA5DXF008Q551PT1YWHRGRQ"} {
+		data, err := zrockford32.StdEncoding.DecodePrefixedTolerant(s, "key", "-")
+		if err != nil {
+			t.Errorf("DecodePrefixedTolerant %q: error: %v", s, err)
+			continue
+		}
+		if g, e := string(data), "hello, world"; g != e {
+			t.Errorf("DecodePrefixedTolerant %q wrong result: %q != %q", s, g, e)
+		}
+	}
+
+	for s, e := range map[string]error{
+		"inv_PB1SA5DX0DVHF4":  zrockford32.ErrPrefix,
+		"ord_PB1SA5DX0DVHF4":  zrockford32.ErrChecksum,
+		"PB1SA5DXF008Q551PT1": zrockford32.ErrPrefix,
+	} {
+		if _, err := zrockford32.StdEncoding.DecodePrefixedTolerant(s, "key", "-"); err != e {
+			t.Errorf("DecodePrefixedTolerant %q: wrong error: %v != %v", s, err, e)
+		}
+	}
+}
+
+func TestPrefixedBad(t *testing.T) {
+	for _, hrp := range []string{"", "in_v", "in v", "caf\xc3\xa9", string(make([]byte, 84))} {
+		if _, err := zrockford32.StdEncoding.EncodePrefixed(hrp, []byte("hi")); err != zrockford32.ErrPrefix {
+			t.Errorf("EncodePrefixed %q: wrong error: %v", hrp, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		input string
+		err   error
+	}{
+		{"PB1SA5DX0DVHF4", zrockford32.ErrPrefix},
+		{"_PB1SA5DX0DVHF4", zrockford32.ErrPrefix},
+		{"inv_", zrockford32.ErrChecksum},
+		{"inv_HF4", zrockford32.ErrChecksum},
+		{"inv_PB1SA!DX0DVHF4", zrockford32.CorruptInputError(9)},
+		{"inv_PBWTBSAEMR", zrockford32.CorruptInputError(7)},
+	} {
+		if _, _, err := zrockford32.StdEncoding.DecodePrefixed(tc.input); err != tc.err {
+			t.Errorf("DecodePrefixed %q: wrong error: %v != %v", tc.input, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Package qr generates QR codes, in pure Go, for printing zrockford32 codes in
+// scannable form.
+//
+// Text made only of digits, uppercase letters and the symbols " $%*+-./:" is
+// encoded in alphanumeric mode, which packs two characters in 11 bits: codes of
+// the standard encoding, grouped or not, fit in it and make the densest QR
+// codes. Other text, such as lowercase codes, is encoded byte by byte.
+package qr
+
+import (
+	"errors"
+	"strings"
+)
+
+// Level is the error correction level of a QR code, the share of it which can
+// be damaged while keeping it readable.
+type Level int
+
+const (
+	// L recovers about 7% of the code.
+	L Level = iota
+	// M recovers about 15% of the code.
+	M
+	// Q recovers about 25% of the code.
+	Q
+	// H recovers about 30% of the code.
+	H
+)
+
+// ErrTooLong is returned when text does not fit in a QR code of version 40.
+var ErrTooLong = errors.New("qr: text too long for a QR code")
+
+const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// Code is a QR code, a square of Size by Size modules, without the quiet zone
+// which must surround it.
+type Code struct {
+	Size    int
+	Version int
+	Level   Level
+
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// Black reports whether the module at column x and row y is dark. Modules
+// outside of the code are light.
+func (c *Code) Black(x, y int) bool {
+	return 0 <= x && x < c.Size && 0 <= y && y < c.Size && c.modules[y][x]
+}
+
+// IsAlphanumeric reports whether text can be encoded in alphanumeric mode.
+func IsAlphanumeric(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if strings.IndexByte(alphanumeric, text[i]) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Encode returns the smallest QR code holding text at the given level.
+func Encode(text string, level Level) (*Code, error) {
+	alnum := IsAlphanumeric(text)
+
+	for version := 1; version <= 40; version++ {
+		capacity := dataCodewords(version, level) * 8
+		if segmentBits(len(text), version, alnum) <= capacity {
+			c := &Code{Size: version*4 + 17, Version: version, Level: level}
+			c.draw(encodeData(text, version, alnum, capacity/8))
+			return c, nil
+		}
+	}
+
+	return nil, ErrTooLong
+}
+
+// Data
+
+func countBits(version int, alnum bool) int {
+	switch {
+	case alnum && version <= 9:
+		return 9
+	case alnum && version <= 26:
+		return 11
+	case alnum:
+		return 13
+	case version <= 9:
+		return 8
+	}
+
+	return 16
+}
+
+func segmentBits(n, version int, alnum bool) int {
+	data := n * 8
+	if alnum {
+		data = n/2*11 + n%2*6
+	}
+
+	if n >= 1<<uint(countBits(version, alnum)) {
+		return 1 << 30
+	}
+
+	return 4 + countBits(version, alnum) + data
+}
+
+type bitBuffer struct {
+	bytes []byte
+	n     int
+}
+
+func (b *bitBuffer) append(v, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if b.n%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if v>>uint(i)&1 != 0 {
+			b.bytes[b.n/8] |= 0x80 >> uint(b.n%8)
+		}
+		b.n++
+	}
+}
+
+// encodeData returns the data codewords: the segment, a terminator and padding
+// up to the capacity of the version.
+func encodeData(text string, version int, alnum bool, capacity int) []byte {
+	var b bitBuffer
+
+	if alnum {
+		b.append(0x2, 4)
+		b.append(len(text), countBits(version, alnum))
+		for i := 0; i+1 < len(text); i += 2 {
+			b.append(strings.IndexByte(alphanumeric, text[i])*45+strings.IndexByte(alphanumeric, text[i+1]), 11)
+		}
+		if len(text)%2 != 0 {
+			b.append(strings.IndexByte(alphanumeric, text[len(text)-1]), 6)
+		}
+	} else {
+		b.append(0x4, 4)
+		b.append(len(text), countBits(version, alnum))
+		for i := 0; i < len(text); i++ {
+			b.append(int(text[i]), 8)
+		}
+	}
+
+	b.append(0, min(4, capacity*8-b.n))
+	b.append(0, (8-b.n%8)%8)
+	for pad := 0xEC; len(b.bytes) < capacity; pad ^= 0xEC ^ 0x11 {
+		b.append(pad, 8)
+	}
+
+	return b.bytes
+}
+
+// Error correction
+
+// Error correction codewords per block and number of blocks, by level and
+// version, from the specification.
+var eccPerBlock = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var eccBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// rawModules returns the number of modules of a version left for data and
+// error correction once the function patterns are drawn.
+func rawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+
+	return n
+}
+
+func dataCodewords(version int, level Level) int {
+	return rawModules(version)/8 - eccPerBlock[level][version]*eccBlocks[level][version]
+}
+
+// interleave splits data in blocks, appends their error correction codewords
+// and interleaves them all.
+func interleave(data []byte, version int, level Level) []byte {
+	blocks := eccBlocks[level][version]
+	eccLen := eccPerBlock[level][version]
+	raw := rawModules(version) / 8
+	short := blocks - raw%blocks
+	shortLen := raw / blocks
+
+	generator := rsGenerator(eccLen)
+	var all [][]byte
+	for i, k := 0, 0; i < blocks; i++ {
+		n := shortLen - eccLen
+		if i >= short {
+			n++
+		}
+
+		block := make([]byte, 0, shortLen+1)
+		block = append(block, data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, generator)
+		if i < short {
+			block = append(block, 0)
+		}
+		all = append(all, append(block, ecc...))
+	}
+
+	result := make([]byte, 0, raw)
+	for i := range all[0] {
+		for j, block := range all {
+			// Short blocks have no codeword at the padding position.
+			if i != shortLen-eccLen || j >= short {
+				result = append(result, block[i])
+			}
+		}
+	}
+
+	return result
+}
+
+// Reed-Solomon over GF(256), with the polynomial x^8 + x^4 + x^3 + x^2 + 1 of
+// the specification.
+
+func gf256Mul(a, b byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ z>>7*0x11D
+		z ^= int(b>>uint(i)&1) * int(a)
+	}
+
+	return byte(z)
+}
+
+func rsGenerator(degree int) []byte {
+	generator := make([]byte, degree)
+	generator[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range generator {
+			generator[j] = gf256Mul(generator[j], root)
+			if j+1 < len(generator) {
+				generator[j] ^= generator[j+1]
+			}
+		}
+		root = gf256Mul(root, 0x02)
+	}
+
+	return generator
+}
+
+func rsRemainder(data, generator []byte) []byte {
+	remainder := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[len(remainder)-1] = 0
+		for i, g := range generator {
+			remainder[i] ^= gf256Mul(g, factor)
+		}
+	}
+
+	return remainder
+}
+
+func min(a, b int) int {
+	if a <= b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,253 @@
+package qr_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32/qr"
+)
+
+// The code below was checked with an independent reader.
+var pb1sa5dx = []string{
+	"#######..#....#######",
+	"#.....#.####..#.....#",
+	"#.###.#..#.##.#.###.#",
+	"#.###.#..###..#.###.#",
+	"#.###.#.#.###.#.###.#",
+	"#.....#...##..#.....#",
+	"#######.#.#.#.#######",
+	".....................",
+	"#.#.#.#.....#...#..#.",
+	"#...#...#..#.#.#.#.#.",
+	"#.#...###.##.###..#.#",
+	"##.##...######.####.#",
+	".#..###...##.###.####",
+	"........#.#...##.#.#.",
+	"#######..##.#...#..##",
+	"#.....#..#....##...#.",
+	"#.###.#.#.#.#.#.##.##",
+	"#.###.#..###.#...#.#.",
+	"#.###.#.#..#.######.#",
+	"#.....#...####..#.###",
+	"#######.#.##.##.###.#",
+}
+
+func modules(c *qr.Code) []string {
+	rows := make([]string, c.Size)
+	for y := range rows {
+		var b strings.Builder
+		for x := 0; x < c.Size; x++ {
+			if c.Black(x, y) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		rows[y] = b.String()
+	}
+
+	return rows
+}
+
+func TestEncode(t *testing.T) {
+	c, err := qr.Encode("PB1SA5DX", qr.M)
+	if err != nil {
+		t.Fatalf("Encode: error: %v", err)
+	}
+	if g, e := c.Version, 1; g != e {
+		t.Fatalf("Encode version wrong result: %d != %d", g, e)
+	}
+	if g, e := c.Size, 21; g != e {
+		t.Fatalf("Encode size wrong result: %d != %d", g, e)
+	}
+
+	rows := modules(c)
+	for y := range rows {
+		if g, e := rows[y], pb1sa5dx[y]; g != e {
+			t.Errorf("Encode row %d wrong result: %q != %q", y, g, e)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	for _, tc := range []struct {
+		text    string
+		level   qr.Level
+		version int
+	}{
+		// Capacities of version 1 from the specification.
+		{strings.Repeat("A", 25), qr.L, 1},
+		{strings.Repeat("A", 26), qr.L, 2},
+		{strings.Repeat("A", 20), qr.M, 1},
+		{strings.Repeat("A", 21), qr.M, 2},
+		{strings.Repeat("A", 10), qr.H, 1},
+		{strings.Repeat("a", 17), qr.L, 1},
+		{strings.Repeat("a", 18), qr.L, 2},
+		{strings.Repeat("a", 7), qr.H, 1},
+		// Capacities of version 40.
+		{strings.Repeat("A", 4296), qr.L, 40},
+		{strings.Repeat("a", 1273), qr.H, 40},
+	} {
+		c, err := qr.Encode(tc.text, tc.level)
+		if err != nil {
+			t.Errorf("Encode %d characters at level %d: error: %v", len(tc.text), tc.level, err)
+			continue
+		}
+		if g, e := c.Version, tc.version; g != e {
+			t.Errorf("Encode %d characters at level %d wrong version: %d != %d", len(tc.text), tc.level, g, e)
+		}
+		if g, e := c.Size, tc.version*4+17; g != e {
+			t.Errorf("Encode %d characters at level %d wrong size: %d != %d", len(tc.text), tc.level, g, e)
+		}
+	}
+}
+
+func TestTooLong(t *testing.T) {
+	for _, tc := range []struct {
+		text  string
+		level qr.Level
+	}{
+		{strings.Repeat("A", 4297), qr.L},
+		{strings.Repeat("a", 1274), qr.H},
+	} {
+		if _, err := qr.Encode(tc.text, tc.level); err != qr.ErrTooLong {
+			t.Errorf("Encode %d characters at level %d: wrong error: %v", len(tc.text), tc.level, err)
+		}
+	}
+}
+
+func TestIsAlphanumeric(t *testing.T) {
+	for _, tc := range []struct {
+		text  string
+		match bool
+	}{
+		{"", true},
+		{"YBNDRFG8EJKMCPQX0T1VW", true},
+		{"PB1S-A5DX $%*+./:", true},
+		{"pb1sa5dx", false},
+		{"inv_PB1SA5DX", false},
+	} {
+		if g, e := qr.IsAlphanumeric(tc.text), tc.match; g != e {
+			t.Errorf("IsAlphanumeric %q wrong result: %v != %v", tc.text, g, e)
+		}
+	}
+}
+
+// TestFormat reads the format information around the top left finder pattern
+// and checks it against the table of the specification.
+func TestFormat(t *testing.T) {
+	for _, tc := range []struct {
+		level qr.Level
+		// Format information for mask 0.
+		mask0 int
+	}{
+		{qr.L, 0x77C4},
+		{qr.M, 0x5412},
+		{qr.Q, 0x355F},
+		{qr.H, 0x1689},
+	} {
+		c, err := qr.Encode("PB1SA5DXF008Q551PT1YW", tc.level)
+		if err != nil {
+			t.Fatalf("Encode: error: %v", err)
+		}
+
+		bits := 0
+		for i := 14; i >= 9; i-- {
+			bits = bits<<1 | bit(c, 14-i, 8)
+		}
+		bits = bits<<1 | bit(c, 7, 8)
+		bits = bits<<1 | bit(c, 8, 8)
+		bits = bits<<1 | bit(c, 8, 7)
+		for i := 5; i >= 0; i-- {
+			bits = bits<<1 | bit(c, 8, i)
+		}
+
+		// The level is in the top two bits, only the mask changes the rest.
+		if g, e := bits>>13, tc.mask0>>13; g != e {
+			t.Errorf("Encode level %d wrong level bits: %02b != %02b", tc.level, g, e)
+		}
+	}
+}
+
+func bit(c *qr.Code, x, y int) int {
+	if c.Black(x, y) {
+		return 1
+	}
+
+	return 0
+}
+
+func TestWritePNG(t *testing.T) {
+	c, err := qr.Encode("PB1SA5DX", qr.M)
+	if err != nil {
+		t.Fatalf("Encode: error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WritePNG(&buf, 3); err != nil {
+		t.Fatalf("WritePNG: error: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode: error: %v", err)
+	}
+
+	if g, e := img.Bounds().Dx(), (21+2*qr.QuietZone)*3; g != e {
+		t.Fatalf("WritePNG width wrong result: %d != %d", g, e)
+	}
+	for y := -qr.QuietZone; y < c.Size+qr.QuietZone; y++ {
+		for x := -qr.QuietZone; x < c.Size+qr.QuietZone; x++ {
+			r, _, _, _ := img.At((x+qr.QuietZone)*3+1, (y+qr.QuietZone)*3+1).RGBA()
+			if g, e := r == 0, c.Black(x, y); g != e {
+				t.Fatalf("WritePNG module %d, %d wrong result: %v != %v", x, y, g, e)
+			}
+		}
+	}
+}
+
+func TestSVG(t *testing.T) {
+	c, err := qr.Encode("PB1SA5DX", qr.M)
+	if err != nil {
+		t.Fatalf("Encode: error: %v", err)
+	}
+
+	svg := c.SVG()
+	if !strings.HasPrefix(svg, "<svg ") || !strings.Contains(svg, `viewBox="0 0 29 29"`) {
+		t.Errorf("SVG wrong header: %s", svg)
+	}
+	// The top left finder pattern starts with a run of seven dark modules.
+	if !strings.Contains(svg, `d="M4,4h7v1h-7z`) {
+		t.Errorf("SVG missing first run: %s", svg)
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	c, err := qr.Encode("PB1SA5DX", qr.M)
+	if err != nil {
+		t.Fatalf("Encode: error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		invert bool
+		// The quiet zone is drawn in blocks unless inverted.
+		first string
+	}{
+		{false, strings.Repeat("█", 29)},
+		{true, strings.Repeat(" ", 29)},
+	} {
+		lines := strings.Split(strings.TrimSuffix(c.Terminal(tc.invert), "\n"), "\n")
+		if g, e := len(lines), (21+2*qr.QuietZone+1)/2; g != e {
+			t.Errorf("Terminal %v lines wrong result: %d != %d", tc.invert, g, e)
+		}
+		for i, line := range lines {
+			if g, e := len([]rune(line)), 21+2*qr.QuietZone; g != e {
+				t.Errorf("Terminal %v line %d width wrong result: %d != %d", tc.invert, i, g, e)
+			}
+		}
+		if g, e := lines[0], tc.first; g != e {
+			t.Errorf("Terminal %v first line wrong result: %q != %q", tc.invert, g, e)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package qr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// QuietZone is the width, in modules, of the light border which readers need
+// around a code, included by every rendering.
+const QuietZone = 4
+
+// Image returns the code as an image with scale pixels per module.
+func (c *Code) Image(scale int) image.Image {
+	scale = max(scale, 1)
+	size := (c.Size + 2*QuietZone) * scale
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if c.Black(x/scale-QuietZone, y/scale-QuietZone) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	return img
+}
+
+// WritePNG writes the code to w as a PNG image with scale pixels per module.
+func (c *Code) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, c.Image(scale))
+}
+
+// SVG returns the code as an SVG image one unit per module, drawn as a single
+// path so that it scales without seams.
+func (c *Code) SVG() string {
+	var b strings.Builder
+	size := c.Size + 2*QuietZone
+
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 %d %d\" shape-rendering=\"crispEdges\">\n", size, size)
+	fmt.Fprintf(&b, "<rect width=\"100%%\" height=\"100%%\" fill=\"#fff\"/>\n")
+	b.WriteString("<path fill=\"#000\" d=\"")
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Black(x, y) {
+				continue
+			}
+			// Merge horizontal runs of dark modules.
+			n := 1
+			for c.Black(x+n, y) {
+				n++
+			}
+			fmt.Fprintf(&b, "M%d,%dh%dv1h-%dz", x+QuietZone, y+QuietZone, n, n)
+			x += n - 1
+		}
+	}
+	b.WriteString("\"/>\n</svg>\n")
+
+	return b.String()
+}
+
+// Terminal returns the code as lines of text, two rows of modules per line
+// with half block characters. Light modules are drawn as blocks, so that the
+// code reads on terminals with light text on a dark background; invert draws
+// the dark modules instead, for dark text on a light background.
+func (c *Code) Terminal(invert bool) string {
+	var b strings.Builder
+
+	for y := -QuietZone; y < c.Size+QuietZone; y += 2 {
+		for x := -QuietZone; x < c.Size+QuietZone; x++ {
+			top, bottom := !c.Black(x, y), !c.Black(x, y+1)
+			if y+1 >= c.Size+QuietZone {
+				// The last line of an odd height has no bottom row.
+				bottom = invert
+			}
+			if invert {
+				top, bottom = !top, !bottom
+			}
+
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package zrockford32
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// Random codes
+
+const maxGenerateAttempts = 100
+
+type GenerateOption func(*generateConfig)
+
+type generateConfig struct {
+	encoding  *Encoding
+	rand      io.Reader
+	groupSize int
+	groupSep  string
+	check     bool
+	blocklist *Blocklist
+	accept    func(code string) bool
+}
+
+func WithEncoding(encoding *Encoding) GenerateOption {
+	return func(c *generateConfig) {
+		c.encoding = encoding
+	}
+}
+
+// WithRand replaces crypto/rand as the source of randomness, which is mostly
+// useful for deterministic tests.
+func WithRand(reader io.Reader) GenerateOption {
+	return func(c *generateConfig) {
+		c.rand = reader
+	}
+}
+
+func WithGrouping(size int, sep string) GenerateOption {
+	return func(c *generateConfig) {
+		c.groupSize = size
+		c.groupSep = sep
+	}
+}
+
+func WithCheckSymbol() GenerateOption {
+	return func(c *generateConfig) {
+		c.check = true
+	}
+}
+
+// WithBlocklist rejects and regenerates codes matched by blocklist.
+func WithBlocklist(blocklist *Blocklist) GenerateOption {
+	return func(c *generateConfig) {
+		c.blocklist = blocklist
+	}
+}
+
+// WithAccept rejects and regenerates codes for which accept returns false, such
+// as codes which IsFilenameSafe or IsDNSLabel reject.
+func WithAccept(accept func(code string) bool) GenerateOption {
+	return func(c *generateConfig) {
+		c.accept = accept
+	}
+}
+
+type RandomCode struct {
+	Code string
+	// Entropy is the number of random bits carried by Code.
+	Entropy int
+}
+
+func GenerateCode(bits int, opts ...GenerateOption) (RandomCode, error) {
+	if bits <= 0 {
+		return RandomCode{}, errors.New("cannot generate a code of less than one bit")
+	}
+
+	c := generateConfig{encoding: StdEncoding, rand: rand.Reader}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	buffer := make([]byte, (bits+7)/8)
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		if _, err := io.ReadFull(c.rand, buffer); err != nil {
+			return RandomCode{}, err
+		}
+
+		code := c.encoding.EncodeBitsToString(buffer, bits)
+		if c.check {
+			var err error
+			if code, err = c.encoding.AddCheckSymbol(code); err != nil {
+				return RandomCode{}, err
+			}
+		}
+		code = Group(code, c.groupSize, c.groupSep)
+
+		if !c.blocklist.Match(code) && (c.accept == nil || c.accept(code)) {
+			return RandomCode{Code: code, Entropy: bits}, nil
+		}
+	}
+
+	return RandomCode{}, errors.New("could not generate an accepted code outside of the blocklist")
+}
+
+// NewRandomString returns a random code of n symbols, before grouping and
+// check symbol.
+func NewRandomString(n int, opts ...GenerateOption) (string, error) {
+	code, err := GenerateCode(n*5, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return code.Code, nil
+}
@@ -0,0 +1,100 @@
+package zrockford32_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func TestGenerateCode(t *testing.T) {
+	for _, tc := range []struct {
+		bits    int
+		encoded string
+	}{
+		{1, "0"},
+		{5, "9"},
+		{8, "9H"},
+		{20, "9999"},
+		{80, "9999999999999999"},
+	} {
+		r := bytes.NewReader(bytes.Repeat([]byte{0xff}, 16))
+		code, err := zrockford32.GenerateCode(tc.bits, zrockford32.WithRand(r))
+		if err != nil {
+			t.Errorf("GenerateCode %d bits: error: %v", tc.bits, err)
+			continue
+		}
+		if g, e := code.Code, tc.encoded; g != e {
+			t.Errorf("GenerateCode %d bits wrong result: %q != %q", tc.bits, g, e)
+		}
+		if g, e := code.Entropy, tc.bits; g != e {
+			t.Errorf("GenerateCode %d bits wrong entropy: %d != %d", tc.bits, g, e)
+		}
+	}
+}
+
+func TestGenerateCodeOptions(t *testing.T) {
+	r := bytes.NewReader([]byte{0x10, 0x11, 0x10})
+	code, err := zrockford32.GenerateCode(20,
+		zrockford32.WithRand(r),
+		zrockford32.WithEncoding(zrockford32.LwrEncoding),
+		zrockford32.WithCheckSymbol(),
+		zrockford32.WithGrouping(3, "-"),
+	)
+	if err != nil {
+		t.Fatalf("GenerateCode: error: %v", err)
+	}
+
+	check, _ := zrockford32.LwrEncoding.AddCheckSymbol("nyet")
+	if g, e := code.Code, zrockford32.Group(check, 3, "-"); g != e {
+		t.Errorf("GenerateCode wrong result: %q != %q", g, e)
+	}
+}
+
+func TestGenerateCodeBlocklist(t *testing.T) {
+	r := bytes.NewReader([]byte{0x10, 0x11, 0x10, 0xff, 0xff, 0xff})
+	code, err := zrockford32.GenerateCode(20,
+		zrockford32.WithRand(r),
+		zrockford32.WithBlocklist(zrockford32.NewBlocklist("nyet")),
+	)
+	if err != nil {
+		t.Fatalf("GenerateCode: error: %v", err)
+	}
+	if g, e := code.Code, "9999"; g != e {
+		t.Errorf("GenerateCode wrong result: %q != %q", g, e)
+	}
+}
+
+func TestGenerateCodeErrors(t *testing.T) {
+	if _, err := zrockford32.GenerateCode(0); err == nil {
+		t.Errorf("GenerateCode 0 bits succeeded")
+	}
+	if _, err := zrockford32.GenerateCode(80, zrockford32.WithRand(strings.NewReader("short"))); err == nil {
+		t.Errorf("GenerateCode with short reader succeeded")
+	}
+	blockAll := zrockford32.NewBlocklist(strings.Split("ybndrfg8ejkmcpqx0t1vw2sza345h769", "")...)
+	if _, err := zrockford32.GenerateCode(80, zrockford32.WithBlocklist(blockAll)); err == nil {
+		t.Errorf("GenerateCode with exhaustive blocklist succeeded")
+	}
+}
+
+func TestNewRandomString(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		s, err := zrockford32.NewRandomString(16)
+		if err != nil {
+			t.Fatalf("NewRandomString: error: %v", err)
+		}
+		if len(s) != 16 {
+			t.Fatalf("NewRandomString wrong length: %q", s)
+		}
+		if _, err := zrockford32.StdEncoding.DecodeBitsString(s, 80); err != nil {
+			t.Fatalf("NewRandomString produced undecodable %q: %v", s, err)
+		}
+		if seen[s] {
+			t.Fatalf("NewRandomString repeated %q", s)
+		}
+		seen[s] = true
+	}
+}
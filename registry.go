@@ -0,0 +1,57 @@
+package zrockford32
+
+import (
+	"sort"
+	"sync"
+)
+
+// Registry
+//
+// Encodings can be registered under a name so that tools and higher level
+// formats can refer to them, the standard ones being "std" and "lwr".
+
+var registry = struct {
+	sync.RWMutex
+	encodings map[string]*Encoding
+}{
+	encodings: map[string]*Encoding{
+		"std": StdEncoding,
+		"lwr": LwrEncoding,
+	},
+}
+
+// RegisterEncoding panics if name is empty or already registered.
+func RegisterEncoding(name string, e *Encoding) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if len(name) == 0 {
+		panic("zrockford32: encoding name must not be empty")
+	}
+	if _, ok := registry.encodings[name]; ok {
+		panic("zrockford32: encoding " + name + " is already registered")
+	}
+
+	registry.encodings[name] = e
+}
+
+func LookupEncoding(name string) (*Encoding, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	e, ok := registry.encodings[name]
+	return e, ok
+}
+
+func Encodings() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	names := make([]string, 0, len(registry.encodings))
+	for name := range registry.encodings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,50 @@
+package zrockford32_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func TestLookupEncoding(t *testing.T) {
+	for name, e := range map[string]*zrockford32.Encoding{
+		"std": zrockford32.StdEncoding,
+		"lwr": zrockford32.LwrEncoding,
+	} {
+		if g, ok := zrockford32.LookupEncoding(name); !ok || g != e {
+			t.Errorf("LookupEncoding %q wrong result: %p, %v", name, g, ok)
+		}
+	}
+
+	if g, e := zrockford32.StdEncoding.Alphabet(), "YBNDRFG8EJKMCPQX0T1VW2SZA345H769"; g != e {
+		t.Errorf("Alphabet wrong result: %q != %q", g, e)
+	}
+
+	if _, ok := zrockford32.LookupEncoding("missing"); ok {
+		t.Errorf("LookupEncoding found a missing encoding")
+	}
+}
+
+func TestRegisterEncoding(t *testing.T) {
+	e := zrockford32.NewEncoding("ybndrfg8ejkmcpqxot1uwisza345h769")
+	zrockford32.RegisterEncoding("test-zbase32", e)
+
+	if g, ok := zrockford32.LookupEncoding("test-zbase32"); !ok || g != e {
+		t.Errorf("LookupEncoding wrong result: %p, %v", g, ok)
+	}
+	if g, e := zrockford32.Encodings(), []string{"lwr", "std", "test-zbase32"}; !reflect.DeepEqual(g, e) {
+		t.Errorf("Encodings wrong result: %q != %q", g, e)
+	}
+
+	for _, name := range []string{"", "std"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterEncoding %q did not panic", name)
+				}
+			}()
+			zrockford32.RegisterEncoding(name, e)
+		}()
+	}
+}
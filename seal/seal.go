@@ -0,0 +1,201 @@
+// Package seal protects secrets with a passphrase as printable zrockford32
+// codes, for paper backups of keys.
+//
+// The key is derived from the passphrase with Argon2id and a random salt, and
+// the secret encrypted with AES-256-GCM. The code starts with a version and
+// the Argon2id parameters, which are authenticated along with the secret, and
+// is written as a prefixed code so that typos are caught by its checksum before
+// any decryption is attempted:
+//
+//	seal_YBND-RFG8-...
+package seal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/bits"
+
+	"github.com/checksum0/go-zrockford32"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	Prefix = "seal"
+
+	version      = 1
+	headerLen    = 4
+	saltLen      = 16
+	keyLen       = 32
+	nonceLen     = 12
+	groupSize    = 4
+	groupSep     = "-"
+	maxMemoryLog = 22
+)
+
+var ErrVersion = errors.New("unsupported sealed code version")
+var ErrOpen = errors.New("wrong passphrase or corrupted sealed code")
+
+// Params are the Argon2id parameters of the key derivation. Memory is in KiB,
+// and must be a power of two.
+type Params struct {
+	Time    uint8
+	Memory  uint32
+	Threads uint8
+}
+
+// DefaultParams follow the recommendation of RFC 9106 for memory constrained
+// environments.
+var DefaultParams = Params{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// DefaultMaxParams are the highest parameters which OpenCode accepts by
+// default, four times DefaultParams.
+var DefaultMaxParams = Params{Time: 12, Memory: 256 * 1024, Threads: 16}
+
+type config struct {
+	params    Params
+	maxParams Params
+	rand      io.Reader
+	group     int
+}
+
+type Option func(*config)
+
+func WithParams(params Params) Option {
+	return func(c *config) {
+		c.params = params
+	}
+}
+
+// WithMaxParams sets the highest parameters which OpenCode accepts,
+// DefaultMaxParams by default. They are read from the code before it can be
+// authenticated, so the limit bounds the time and memory which a corrupted or
+// hostile code costs. Codes sealed with higher parameters need a matching
+// limit to be opened.
+func WithMaxParams(max Params) Option {
+	return func(c *config) {
+		c.maxParams = max
+	}
+}
+
+// WithRand sets the source of the salt, crypto/rand by default.
+func WithRand(r io.Reader) Option {
+	return func(c *config) {
+		c.rand = r
+	}
+}
+
+// WithGrouping splits the encoded secret in groups of size symbols, 4 by
+// default, or not at all if size is 0.
+func WithGrouping(size int) Option {
+	return func(c *config) {
+		c.group = size
+	}
+}
+
+// Validate reports whether p can be used to seal: time and threads must be at
+// least 1, and memory a power of two of at least 8 KiB per thread and at most
+// 4 GiB.
+func (p Params) Validate() error {
+	if p.Time < 1 || p.Threads < 1 {
+		return errors.New("seal time and threads must be at least 1")
+	}
+	if bits.OnesCount32(p.Memory) != 1 || bits.TrailingZeros32(p.Memory) > maxMemoryLog {
+		return errors.New("seal memory must be a power of two of at most 4 GiB")
+	}
+	if p.Memory < 8*uint32(p.Threads) {
+		return errors.New("seal memory must be at least 8 KiB per thread")
+	}
+
+	return nil
+}
+
+func (p Params) exceeds(max Params) bool {
+	return p.Time > max.Time || p.Memory > max.Memory || p.Threads > max.Threads
+}
+
+func SealToCode(secret, passphrase []byte, opts ...Option) (string, error) {
+	c := &config{params: DefaultParams, rand: rand.Reader, group: groupSize}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if err := c.params.Validate(); err != nil {
+		return "", err
+	}
+
+	header := []byte{version, c.params.Time, byte(bits.TrailingZeros32(c.params.Memory)), c.params.Threads}
+
+	payload := make([]byte, headerLen+saltLen, headerLen+saltLen+len(secret)+16)
+	copy(payload, header)
+	if _, err := io.ReadFull(c.rand, payload[headerLen:]); err != nil {
+		return "", err
+	}
+
+	aead, nonce, err := derive(passphrase, payload[headerLen:], c.params)
+	if err != nil {
+		return "", err
+	}
+	payload = aead.Seal(payload, nonce, secret, payload[:headerLen+saltLen])
+
+	return zrockford32.StdEncoding.EncodePrefixedGrouped(Prefix, payload, c.group, groupSep)
+}
+
+// OpenCode accepts codes in either case, with any grouping. It returns ErrOpen
+// for codes whose parameters exceed the limit set by WithMaxParams, the only
+// option it uses.
+func OpenCode(code string, passphrase []byte, opts ...Option) ([]byte, error) {
+	c := &config{maxParams: DefaultMaxParams}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	payload, err := zrockford32.StdEncoding.DecodePrefixedTolerant(code, Prefix, groupSep)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) < headerLen || payload[0] != version {
+		return nil, ErrVersion
+	}
+	if len(payload) < headerLen+saltLen+16 || payload[2] > maxMemoryLog {
+		return nil, ErrOpen
+	}
+
+	params := Params{Time: payload[1], Memory: 1 << payload[2], Threads: payload[3]}
+	if err := params.Validate(); err != nil || params.exceeds(c.maxParams) {
+		return nil, ErrOpen
+	}
+
+	aead, nonce, err := derive(passphrase, payload[headerLen:headerLen+saltLen], params)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := aead.Open(nil, nonce, payload[headerLen+saltLen:], payload[:headerLen+saltLen])
+	if err != nil {
+		return nil, ErrOpen
+	}
+
+	return secret, nil
+}
+
+// derive returns the AEAD keyed from the passphrase and salt, and the nonce to
+// use with it, which is derived as well since every salt is used once.
+func derive(passphrase, salt []byte, params Params) (cipher.AEAD, []byte, error) {
+	derived := argon2.IDKey(passphrase, salt, uint32(params.Time), params.Memory, params.Threads, keyLen+nonceLen)
+	defer zrockford32.Zeroize(derived[:keyLen])
+
+	block, err := aes.NewCipher(derived[:keyLen])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return aead, derived[keyLen:], nil
+}
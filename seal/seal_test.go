@@ -0,0 +1,149 @@
+package seal_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+	"github.com/checksum0/go-zrockford32/seal"
+)
+
+var testParams = seal.Params{Time: 1, Memory: 256, Threads: 1}
+
+func sealTest(t *testing.T, secret, passphrase string, opts ...seal.Option) string {
+	t.Helper()
+
+	salt := bytes.Repeat([]byte{0x5a}, 16)
+	opts = append([]seal.Option{seal.WithParams(testParams), seal.WithRand(bytes.NewReader(salt))}, opts...)
+	code, err := seal.SealToCode([]byte(secret), []byte(passphrase), opts...)
+	if err != nil {
+		t.Fatalf("SealToCode: error: %v", err)
+	}
+
+	return code
+}
+
+func TestSealVector(t *testing.T) {
+	code := sealTest(t, "hello, world", "correct horse battery staple")
+	if g, e := code, "seal_YRY0-0YK4-MJPF-WS14-MJPF-WS14-MJPF-WS14-X6R1-DGPT-SJ41-R6TP-7063-DAT4-88P7-KBSX-BGVY-Z1Y5-VDJX-42VH-90F"; g != e {
+		t.Errorf("SealToCode wrong result: %q != %q", g, e)
+	}
+}
+
+func TestSealRoundTrip(t *testing.T) {
+	for _, secret := range []string{"", "k", "hello, world", strings.Repeat("\xff", 64)} {
+		code, err := seal.SealToCode([]byte(secret), []byte("passphrase"), seal.WithParams(testParams))
+		if err != nil {
+			t.Fatalf("SealToCode: error: %v", err)
+		}
+
+		opened, err := seal.OpenCode(code, []byte("passphrase"))
+		if err != nil {
+			t.Errorf("OpenCode %q: error: %v", code, err)
+			continue
+		}
+		if g, e := string(opened), secret; g != e {
+			t.Errorf("OpenCode %q wrong result: %q != %q", code, g, e)
+		}
+	}
+}
+
+func TestSealRandomSalt(t *testing.T) {
+	a, _ := seal.SealToCode([]byte("secret"), []byte("passphrase"), seal.WithParams(testParams))
+	b, _ := seal.SealToCode([]byte("secret"), []byte("passphrase"), seal.WithParams(testParams))
+	if a == b {
+		t.Errorf("SealToCode returned the same code twice: %q", a)
+	}
+}
+
+func TestOpenTolerant(t *testing.T) {
+	code := sealTest(t, "hello, world", "passphrase")
+	data := strings.ReplaceAll(strings.TrimPrefix(code, "seal_"), "-", "")
+
+	for _, variant := range []string{
+		strings.ToLower(code),
+		"SEAL_" + data,
+		"seal_" + zrockford32.Group(data, 5, " ") + "\n",
+	} {
+		opened, err := seal.OpenCode(variant, []byte("passphrase"))
+		if err != nil || string(opened) != "hello, world" {
+			t.Errorf("OpenCode %q wrong result: %q, %v", variant, opened, err)
+		}
+	}
+}
+
+func TestOpenBad(t *testing.T) {
+	code := sealTest(t, "hello, world", "passphrase")
+
+	if _, err := seal.OpenCode(code, []byte("wrong passphrase")); err != seal.ErrOpen {
+		t.Errorf("OpenCode wrong passphrase: wrong error: %v", err)
+	}
+
+	typo := code[:len(code)-1] + "Y"
+	if strings.HasSuffix(code, "Y") {
+		typo = code[:len(code)-1] + "B"
+	}
+	if _, err := seal.OpenCode(typo, []byte("passphrase")); err != zrockford32.ErrChecksum {
+		t.Errorf("OpenCode %q: wrong error: %v", typo, err)
+	}
+
+	other, _ := zrockford32.StdEncoding.EncodePrefixed("seal", []byte{2, 1, 8, 1})
+	if _, err := seal.OpenCode(other, []byte("passphrase")); err != seal.ErrVersion {
+		t.Errorf("OpenCode %q: wrong error: %v", other, err)
+	}
+
+	kind, _ := zrockford32.StdEncoding.EncodePrefixed("inv", []byte("hello"))
+	if _, err := seal.OpenCode(kind, []byte("passphrase")); err != zrockford32.ErrPrefix {
+		t.Errorf("OpenCode %q: wrong error: %v", kind, err)
+	}
+}
+
+func TestSealBadParams(t *testing.T) {
+	for _, params := range []seal.Params{
+		{Time: 0, Memory: 256, Threads: 1},
+		{Time: 1, Memory: 300, Threads: 1},
+		{Time: 1, Memory: 8, Threads: 4},
+		{Time: 1, Memory: 256, Threads: 0},
+	} {
+		if _, err := seal.SealToCode([]byte("secret"), []byte("passphrase"), seal.WithParams(params)); err == nil {
+			t.Errorf("SealToCode %+v: no error", params)
+		}
+	}
+}
+
+func TestOpenMaxParams(t *testing.T) {
+	code := sealTest(t, "secret", "passphrase")
+	_, payload, err := zrockford32.StdEncoding.WithIgnoredChars("-").DecodePrefixed(code)
+	if err != nil {
+		t.Fatalf("DecodePrefixed: %v", err)
+	}
+
+	// Codes claiming costly parameters are rejected before the key is
+	// derived, which would take 512 MiB here.
+	for _, header := range [][3]byte{{13, 8, 1}, {1, 19, 1}, {1, 8, 17}} {
+		copy(payload[1:], header[:])
+		forged, err := zrockford32.StdEncoding.EncodePrefixed(seal.Prefix, payload)
+		if err != nil {
+			t.Fatalf("EncodePrefixed: %v", err)
+		}
+
+		if _, err := seal.OpenCode(forged, []byte("passphrase")); err != seal.ErrOpen {
+			t.Errorf("OpenCode with header %v: got %v, expected %v", header, err, seal.ErrOpen)
+		}
+	}
+
+	params := seal.Params{Time: 13, Memory: 256, Threads: 1}
+	code = sealTest(t, "secret", "passphrase", seal.WithParams(params))
+	if _, err := seal.OpenCode(code, []byte("passphrase")); err != seal.ErrOpen {
+		t.Errorf("OpenCode above the default limit: got %v, expected %v", err, seal.ErrOpen)
+	}
+
+	secret, err := seal.OpenCode(code, []byte("passphrase"), seal.WithMaxParams(params))
+	if err != nil {
+		t.Fatalf("OpenCode with a higher limit: %v", err)
+	}
+	if g, e := string(secret), "secret"; g != e {
+		t.Errorf("OpenCode wrong result: %q != %q", g, e)
+	}
+}
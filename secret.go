@@ -0,0 +1,99 @@
+package zrockford32
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"runtime"
+)
+
+// Secrets
+//
+// Decoding secrets such as recovery keys should leave as few copies of them
+// in memory as possible. DecodeInto decodes a string without copying it, into
+// a buffer owned by the caller which can be cleared with Zeroize once the
+// secret is no longer needed. This is best effort: the Go runtime may still
+// have moved or copied the memory, and strings cannot be cleared at all.
+
+// DecodeInto decodes s into dst, which must be large enough for the decoded
+// bytes, DecodedLen(len(s)) always being enough, and returns the number of
+// bytes written. Unlike Decode, nothing is written past them. If dst is too
+// small, io.ErrShortBuffer is returned.
+func (e *Encoding) DecodeInto(dst []byte, s string) (int, error) {
+	return e.decodeInto(dst, s, -1)
+}
+
+func (e *Encoding) DecodeBitsInto(dst []byte, s string, bits int) (int, error) {
+	if bits < 0 {
+		return 0, errors.New("cannot decode a negative bit count")
+	}
+
+	return e.decodeInto(dst, s, bits)
+}
+
+func (e *Encoding) decodeInto(dst []byte, s string, bits int) (int, error) {
+	n, err := decode(e, dst, s, bits)
+	if err != nil {
+		Zeroize(dst[:n])
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Zeroize overwrites b with zeros.
+func Zeroize(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+
+	// Keep the writes from being optimized away as dead stores.
+	runtime.KeepAlive(b)
+}
+
+// SecretBytes holds secret material. It formats as a placeholder with every
+// fmt verb, so that it does not end up in logs by accident, and is set from a
+// zrockford32 string when used as a flag, like Value.
+type SecretBytes []byte
+
+const redacted = "[REDACTED]"
+
+func (s SecretBytes) String() string {
+	return redacted
+}
+
+func (s SecretBytes) GoString() string {
+	return "zrockford32.SecretBytes(" + redacted + ")"
+}
+
+func (s SecretBytes) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		f.Write([]byte(s.GoString()))
+		return
+	}
+
+	f.Write([]byte(redacted))
+}
+
+// Zeroize overwrites the secret with zeros.
+func (s SecretBytes) Zeroize() {
+	Zeroize(s)
+}
+
+var _ flag.Getter = (*SecretBytes)(nil)
+
+func (s *SecretBytes) Set(value string) error {
+	b := make([]byte, StdEncoding.DecodedLen(len(value)))
+	n, err := StdEncoding.DecodeInto(b, value)
+	if err != nil {
+		return err
+	}
+
+	Zeroize(*s)
+	*s = b[:n]
+	return nil
+}
+
+func (s *SecretBytes) Get() interface{} {
+	return *s
+}
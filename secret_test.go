@@ -0,0 +1,101 @@
+package zrockford32_test
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func TestDecodeInto(t *testing.T) {
+	for _, tc := range byteTestsStd {
+		dst := make([]byte, len(tc.decoded), len(tc.decoded)+8)
+		n, err := zrockford32.StdEncoding.DecodeInto(dst, tc.encoded)
+		if err != nil {
+			t.Errorf("DecodeInto %q: error: %v", tc.encoded, err)
+			continue
+		}
+		if g, e := string(dst[:n]), string(tc.decoded); g != e {
+			t.Errorf("DecodeInto %q wrong result: %x != %x", tc.encoded, g, e)
+		}
+		for _, b := range dst[n:cap(dst)] {
+			if b != 0 {
+				t.Errorf("DecodeInto %q wrote past the decoded bytes: %x", tc.encoded, dst[:cap(dst)])
+				break
+			}
+		}
+	}
+}
+
+func TestDecodeBitsInto(t *testing.T) {
+	for _, tc := range bitTestsStd {
+		dst := make([]byte, len(tc.decoded))
+		n, err := zrockford32.StdEncoding.WithConstantTime().DecodeBitsInto(dst, tc.encoded, tc.bits)
+		if err != nil {
+			t.Errorf("DecodeBitsInto %q: error: %v", tc.encoded, err)
+			continue
+		}
+		if g, e := string(dst[:n]), string(tc.decoded); g != e {
+			t.Errorf("DecodeBitsInto %q wrong result: %x != %x", tc.encoded, g, e)
+		}
+	}
+}
+
+func TestDecodeIntoShortBuffer(t *testing.T) {
+	for _, encoding := range []*zrockford32.Encoding{zrockford32.StdEncoding, zrockford32.StdEncoding.WithConstantTime()} {
+		dst := make([]byte, 11)
+		n, err := encoding.DecodeInto(dst, "PB1SA5DXF008Q551PT1YW")
+		if err != io.ErrShortBuffer || n != 0 {
+			t.Errorf("DecodeInto: wrong result: %d, %v", n, err)
+		}
+		if g, e := string(dst), string(make([]byte, 11)); g != e {
+			t.Errorf("DecodeInto left decoded bytes behind: %q", g)
+		}
+	}
+}
+
+func TestZeroize(t *testing.T) {
+	b := []byte("secret")
+	zrockford32.Zeroize(b)
+	if g, e := string(b), "\x00\x00\x00\x00\x00\x00"; g != e {
+		t.Errorf("Zeroize wrong result: %q != %q", g, e)
+	}
+}
+
+func TestSecretBytesFormat(t *testing.T) {
+	s := zrockford32.SecretBytes("hello, world")
+
+	for _, format := range []string{"%s", "%v", "%+v", "%x", "%X", "%q", "%d", "%10s"} {
+		if g, e := fmt.Sprintf(format, s), "[REDACTED]"; g != e {
+			t.Errorf("Sprintf %q wrong result: %q != %q", format, g, e)
+		}
+	}
+	if g, e := fmt.Sprintf("%#v", s), "zrockford32.SecretBytes([REDACTED])"; g != e {
+		t.Errorf("Sprintf %%#v wrong result: %q != %q", g, e)
+	}
+	if g, e := fmt.Sprint(struct{ Key zrockford32.SecretBytes }{s}), "{[REDACTED]}"; g != e {
+		t.Errorf("Sprint wrong result: %q != %q", g, e)
+	}
+}
+
+func TestSecretBytesFlag(t *testing.T) {
+	var f flag.FlagSet
+	var s zrockford32.SecretBytes
+	f.Var(&s, "key", "recovery key")
+	if err := f.Parse([]string{"-key=PB1SA5DXF008Q551PT1YW"}); err != nil {
+		t.Fatalf("parsing flags: %v", err)
+	}
+	if g, e := string(s), "hello, world\n"; g != e {
+		t.Errorf("wrong decode: %q != %q", g, e)
+	}
+	if g, e := f.Lookup("key").Value.String(), "[REDACTED]"; g != e {
+		t.Errorf("flag value not redacted: %q != %q", g, e)
+	}
+
+	s.Zeroize()
+	if g, e := string(s), string(make([]byte, 13)); g != e {
+		t.Errorf("Zeroize wrong result: %q != %q", g, e)
+	}
+}
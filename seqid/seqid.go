@@ -0,0 +1,197 @@
+// Package seqid maps sequential integers to short, non-sequential zrockford32
+// codes, so that identifiers shown to users do not leak how many of them were
+// issued.
+//
+// Values are permuted with a keyed Feistel network over a configurable bit
+// width and then encoded with EncodeBits. The permutation also covers a small
+// attempt counter, which lets Encode pick another code when the first one is
+// rejected by a blocklist while keeping Decode exact. Decode accepts the codes
+// of every attempt, so issued codes do not depend on the blocklist: they keep
+// decoding after it changes, as long as they are not blocked themselves.
+package seqid
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+const (
+	attemptBits = 5
+	rounds      = 8
+	maxBits     = 64 - attemptBits
+)
+
+var ErrInvalid = errors.New("invalid seqid code")
+
+type Codec struct {
+	encoding  *zrockford32.Encoding
+	block     cipher.Block
+	bits      int
+	blocklist func(code string) bool
+}
+
+type Option func(*Codec)
+
+// WithBits sets the width of the values accepted by Encode. Codes are one
+// symbol longer than the bits alone need, the default of 35 bits giving 8 symbol
+// codes.
+func WithBits(bits int) Option {
+	return func(c *Codec) {
+		c.bits = bits
+	}
+}
+
+func WithEncoding(encoding *zrockford32.Encoding) Option {
+	return func(c *Codec) {
+		c.encoding = encoding
+	}
+}
+
+// WithBlocklist installs a hook that rejects codes, for instance ones spelling
+// offensive words. Encode then moves on to the next candidate code for the same
+// value. zrockford32.DefaultBlocklist.Match is a suitable hook.
+func WithBlocklist(blocked func(code string) bool) Option {
+	return func(c *Codec) {
+		c.blocklist = blocked
+	}
+}
+
+func New(key []byte, opts ...Option) (*Codec, error) {
+	if len(key) == 0 {
+		return nil, errors.New("seqid key must not be empty")
+	}
+
+	c := &Codec{encoding: zrockford32.StdEncoding, bits: 35}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.bits < 1 || c.bits > maxBits {
+		return nil, errors.New("seqid bit width must be between 1 and 59")
+	}
+
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	c.block = block
+
+	return c, nil
+}
+
+func (c *Codec) width() int {
+	return c.bits + attemptBits
+}
+
+func (c *Codec) EncodedLen() int {
+	return (c.width() + 4) / 5
+}
+
+// Encode panics if n does not fit in the configured bit width, or if the
+// blocklist rejects every candidate code for n.
+func (c *Codec) Encode(n uint64) string {
+	if n>>uint(c.bits) != 0 {
+		panic("seqid: value out of range")
+	}
+
+	code, ok := c.encode(n)
+	if !ok {
+		panic("seqid: every candidate code is blocked")
+	}
+
+	return code
+}
+
+func (c *Codec) encode(n uint64) (string, bool) {
+	for attempt := uint64(0); attempt < 1<<attemptBits; attempt++ {
+		code := c.format(c.permute(attempt<<uint(c.bits) | n))
+		if c.blocklist == nil || !c.blocklist(code) {
+			return code, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *Codec) Decode(s string) (uint64, error) {
+	if len(s) != c.EncodedLen() {
+		return 0, ErrInvalid
+	}
+
+	decoded, err := c.encoding.DecodeBitsString(s, c.width())
+	if err != nil {
+		return 0, err
+	}
+
+	var buffer [8]byte
+	copy(buffer[:], decoded)
+	w := binary.BigEndian.Uint64(buffer[:]) >> uint(64-c.width())
+
+	// The encoding must not carry stray trailing bits, and blocked codes are
+	// never issued.
+	if c.format(w) != s || c.blocklist != nil && c.blocklist(s) {
+		return 0, ErrInvalid
+	}
+
+	return c.unpermute(w) & (1<<uint(c.bits) - 1), nil
+}
+
+func (c *Codec) format(w uint64) string {
+	var buffer [8]byte
+	binary.BigEndian.PutUint64(buffer[:], w<<uint(64-c.width()))
+
+	return c.encoding.EncodeBitsToString(buffer[:], c.width())
+}
+
+// Feistel network
+
+func (c *Codec) halves() (uint, uint) {
+	left := uint(c.width() / 2)
+	return left, uint(c.width()) - left
+}
+
+func (c *Codec) round(i int, x uint64) uint64 {
+	var block [aes.BlockSize]byte
+	block[0] = byte(i)
+	block[1] = byte(c.bits)
+	binary.BigEndian.PutUint64(block[8:], x)
+	c.block.Encrypt(block[:], block[:])
+
+	return binary.BigEndian.Uint64(block[:8])
+}
+
+func (c *Codec) permute(x uint64) uint64 {
+	lbits, rbits := c.halves()
+	l, r := x>>rbits, x&(1<<rbits-1)
+
+	for i := 0; i < rounds; i++ {
+		if i%2 == 0 {
+			l = (l ^ c.round(i, r)) & (1<<lbits - 1)
+		} else {
+			r = (r ^ c.round(i, l)) & (1<<rbits - 1)
+		}
+	}
+
+	return l<<rbits | r
+}
+
+func (c *Codec) unpermute(x uint64) uint64 {
+	lbits, rbits := c.halves()
+	l, r := x>>rbits, x&(1<<rbits-1)
+
+	for i := rounds - 1; i >= 0; i-- {
+		if i%2 == 0 {
+			l = (l ^ c.round(i, r)) & (1<<lbits - 1)
+		} else {
+			r = (r ^ c.round(i, l)) & (1<<rbits - 1)
+		}
+	}
+
+	return l<<rbits | r
+}
@@ -0,0 +1,189 @@
+package seqid_test
+
+import (
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+	"github.com/checksum0/go-zrockford32/seqid"
+)
+
+type vectorTestCase struct {
+	decoded uint64
+	encoded string
+}
+
+var vectorTests = []vectorTestCase{
+	{0, "61B0TYAS"},
+	{1, "BEJAHTGP"},
+	{2, "BWJFB6JJ"},
+	{3, "WZKTMG9W"},
+	{1000, "RA5XVMWQ"},
+	{1<<35 - 1, "YRGNT53T"},
+}
+
+var vectorTests16 = []vectorTestCase{
+	{0, "H5W50"},
+	{1, "VDBZ0"},
+	{2, "JJ930"},
+	{65535, "218D0"},
+}
+
+func TestVectors(t *testing.T) {
+	c, err := seqid.New([]byte("example key"))
+	if err != nil {
+		t.Fatalf("New: error: %v", err)
+	}
+	for _, tc := range vectorTests {
+		if g, e := c.Encode(tc.decoded), tc.encoded; g != e {
+			t.Errorf("Encode %d wrong result: %q != %q", tc.decoded, g, e)
+		}
+		n, err := c.Decode(tc.encoded)
+		if err != nil {
+			t.Errorf("Decode %q: error: %v", tc.encoded, err)
+			continue
+		}
+		if g, e := n, tc.decoded; g != e {
+			t.Errorf("Decode %q wrong result: %d != %d", tc.encoded, g, e)
+		}
+	}
+}
+
+func TestVectors16(t *testing.T) {
+	c, err := seqid.New([]byte("example key"), seqid.WithBits(16))
+	if err != nil {
+		t.Fatalf("New: error: %v", err)
+	}
+	for _, tc := range vectorTests16 {
+		if g, e := c.Encode(tc.decoded), tc.encoded; g != e {
+			t.Errorf("Encode %d wrong result: %q != %q", tc.decoded, g, e)
+		}
+	}
+}
+
+func TestRoundTripExhaustive(t *testing.T) {
+	c, err := seqid.New([]byte("example key"), seqid.WithBits(12), seqid.WithEncoding(zrockford32.LwrEncoding))
+	if err != nil {
+		t.Fatalf("New: error: %v", err)
+	}
+
+	seen := make(map[string]uint64)
+	for n := uint64(0); n < 1<<12; n++ {
+		s := c.Encode(n)
+		if len(s) != c.EncodedLen() {
+			t.Fatalf("Encode %d wrong length: %q", n, s)
+		}
+		if prev, ok := seen[s]; ok {
+			t.Fatalf("Encode %d and %d collide on %q", prev, n, s)
+		}
+		seen[s] = n
+
+		m, err := c.Decode(s)
+		if err != nil {
+			t.Fatalf("Decode %q: error: %v", s, err)
+		}
+		if m != n {
+			t.Fatalf("Decode %q wrong result: %d != %d", s, m, n)
+		}
+	}
+}
+
+func TestKeyChangesCodes(t *testing.T) {
+	a, _ := seqid.New([]byte("key a"))
+	b, _ := seqid.New([]byte("key b"))
+	if a.Encode(42) == b.Encode(42) {
+		t.Errorf("different keys produced the same code")
+	}
+}
+
+func TestBlocklist(t *testing.T) {
+	plain, _ := seqid.New([]byte("example key"))
+	blocked := plain.Encode(1000)
+
+	c, err := seqid.New([]byte("example key"), seqid.WithBlocklist(func(code string) bool {
+		return code == blocked
+	}))
+	if err != nil {
+		t.Fatalf("New: error: %v", err)
+	}
+
+	s := c.Encode(1000)
+	if s == blocked {
+		t.Fatalf("Encode returned blocked code %q", s)
+	}
+	if n, err := c.Decode(s); err != nil || n != 1000 {
+		t.Errorf("Decode %q: got %d, %v", s, n, err)
+	}
+	if _, err := c.Decode(blocked); err != seqid.ErrInvalid {
+		t.Errorf("Decode blocked %q: wrong error: %v", blocked, err)
+	}
+
+	// Values whose first code is not blocked are unaffected.
+	if g, e := c.Encode(1), plain.Encode(1); g != e {
+		t.Errorf("Encode 1 changed: %q != %q", g, e)
+	}
+
+	// Issued codes keep decoding once the blocklist changes.
+	if n, err := plain.Decode(s); err != nil || n != 1000 {
+		t.Errorf("Decode %q without blocklist: got %d, %v", s, n, err)
+	}
+}
+
+func TestBlockEverything(t *testing.T) {
+	c, _ := seqid.New([]byte("example key"), seqid.WithBlocklist(func(string) bool { return true }))
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Encode did not panic")
+		}
+	}()
+	c.Encode(1)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	c, _ := seqid.New([]byte("example key"), seqid.WithBits(16))
+	for _, s := range []string{"", "H5W5", "H5W500", "H5W5B"} {
+		if _, err := c.Decode(s); err != seqid.ErrInvalid {
+			t.Errorf("Decode %q: wrong error: %v", s, err)
+		}
+	}
+	if _, err := c.Decode("H5W5!"); err == nil {
+		t.Errorf("Decode with illegal symbol succeeded")
+	}
+}
+
+func TestEncodeOutOfRange(t *testing.T) {
+	c, _ := seqid.New([]byte("example key"), seqid.WithBits(16))
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Encode did not panic")
+		}
+	}()
+	c.Encode(1 << 16)
+}
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := seqid.New(nil); err == nil {
+		t.Errorf("New accepted an empty key")
+	}
+	for _, bits := range []int{0, 60} {
+		if _, err := seqid.New([]byte("k"), seqid.WithBits(bits)); err == nil {
+			t.Errorf("New accepted %d bits", bits)
+		}
+	}
+}
+
+func TestDefaultBlocklist(t *testing.T) {
+	c, err := seqid.New([]byte("example key"), seqid.WithBits(16), seqid.WithBlocklist(zrockford32.DefaultBlocklist.Match))
+	if err != nil {
+		t.Fatalf("New: error: %v", err)
+	}
+
+	for n := uint64(0); n < 1<<16; n++ {
+		s := c.Encode(n)
+		if zrockford32.DefaultBlocklist.Match(s) {
+			t.Fatalf("Encode %d returned blocked code %q", n, s)
+		}
+		if m, err := c.Decode(s); err != nil || m != n {
+			t.Fatalf("Decode %q: got %d, %v", s, m, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package shamir
+
+// GF(256)
+//
+// Bytes are treated as elements of GF(2^8), built from the polynomial
+// x^8 + x^4 + x^3 + x + 1 of AES, with 3 as generator.
+
+var gf256Exp [510]byte
+var gf256Log [256]byte
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gf256Exp[i] = byte(x)
+		gf256Exp[i+255] = byte(x)
+		gf256Log[x] = byte(i)
+
+		// Multiply by 3, that is by x + 1.
+		x ^= x << 1
+		if x&0x100 != 0 {
+			x ^= 0x11b
+		}
+	}
+}
+
+func gf256Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return gf256Exp[int(gf256Log[a])+int(gf256Log[b])]
+}
+
+// gf256Div panics if b is zero.
+func gf256Div(a, b byte) byte {
+	if b == 0 {
+		panic("shamir: division by zero")
+	}
+	if a == 0 {
+		return 0
+	}
+
+	return gf256Exp[int(gf256Log[a])+255-int(gf256Log[b])]
+}
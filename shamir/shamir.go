@@ -0,0 +1,205 @@
+// Package shamir splits secrets into shares with Shamir's secret sharing over
+// GF(256), so that any k of n shares recover the secret while fewer reveal
+// nothing about it.
+//
+// Shares are prefixed zrockford32 codes, "share_YBND-RFG8-...", holding a
+// version, the threshold, the index of the share, an identifier common to the
+// shares of a split, and the share itself. A short hash of the secret is split
+// along with it, so that Combine detects shares which do not fit together
+// without the hash being readable from fewer than k shares.
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+const (
+	Prefix = "share"
+
+	version   = 1
+	headerLen = 7
+	idLen     = 4
+	hashLen   = 4
+	groupSize = 4
+	groupSep  = "-"
+)
+
+var (
+	ErrVersion  = errors.New("unsupported share version")
+	ErrMismatch = errors.New("shares do not belong to the same secret")
+	ErrTooFew   = errors.New("not enough shares to recover the secret")
+	ErrInvalid  = errors.New("shares do not recover a valid secret")
+)
+
+type config struct {
+	rand  io.Reader
+	group int
+}
+
+type Option func(*config)
+
+// WithRand sets the source of the random coefficients and identifier,
+// crypto/rand by default.
+func WithRand(r io.Reader) Option {
+	return func(c *config) {
+		c.rand = r
+	}
+}
+
+// WithGrouping splits the encoded shares in groups of size symbols, 4 by
+// default, or not at all if size is 0.
+func WithGrouping(size int) Option {
+	return func(c *config) {
+		c.group = size
+	}
+}
+
+// Split returns n shares of secret, any k of which recover it, with
+// 2 <= k <= n <= 255.
+func Split(secret []byte, n, k int, opts ...Option) ([]string, error) {
+	c := &config{rand: rand.Reader, group: groupSize}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if k < 2 || n < k || n > 255 {
+		return nil, errors.New("shamir needs 2 <= k <= n <= 255")
+	}
+
+	sum := sha256.Sum256(secret)
+	value := append(append([]byte(nil), secret...), sum[:hashLen]...)
+	defer zrockford32.Zeroize(value)
+
+	id := make([]byte, idLen)
+	if _, err := io.ReadFull(c.rand, id); err != nil {
+		return nil, err
+	}
+
+	// Each byte of the value is the constant term of its own random
+	// polynomial of degree k-1.
+	coefficients := make([]byte, len(value)*(k-1))
+	defer zrockford32.Zeroize(coefficients)
+	if _, err := io.ReadFull(c.rand, coefficients); err != nil {
+		return nil, err
+	}
+
+	shares := make([]string, n)
+	for i := range shares {
+		x := byte(i + 1)
+
+		payload := make([]byte, headerLen, headerLen+len(value))
+		payload[0], payload[1], payload[2] = version, byte(k), x
+		copy(payload[3:], id)
+
+		for j, v := range value {
+			y := byte(0)
+			for d := k - 2; d >= 0; d-- {
+				y = gf256Mul(y, x) ^ coefficients[j*(k-1)+d]
+			}
+			payload = append(payload, gf256Mul(y, x)^v)
+		}
+
+		code, err := zrockford32.StdEncoding.EncodePrefixedGrouped(Prefix, payload, c.group, groupSep)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = code
+	}
+
+	return shares, nil
+}
+
+type share struct {
+	threshold byte
+	x         byte
+	id        []byte
+	y         []byte
+}
+
+// parse accepts shares in either case, with any grouping.
+func parse(s string) (*share, error) {
+	payload, err := zrockford32.StdEncoding.DecodePrefixedTolerant(s, Prefix, groupSep)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) < 1 || payload[0] != version {
+		return nil, ErrVersion
+	}
+	if len(payload) < headerLen+hashLen || payload[1] < 2 || payload[2] == 0 {
+		return nil, ErrInvalid
+	}
+
+	return &share{threshold: payload[1], x: payload[2], id: payload[3:headerLen], y: payload[headerLen:]}, nil
+}
+
+// Combine recovers the secret from at least as many shares as the threshold
+// they were split with. Repeated shares are only counted once.
+func Combine(shares ...string) ([]byte, error) {
+	var points []*share
+
+	for _, s := range shares {
+		p, err := parse(s)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(points) > 0 {
+			first := points[0]
+			if p.threshold != first.threshold || string(p.id) != string(first.id) || len(p.y) != len(first.y) {
+				return nil, ErrMismatch
+			}
+		}
+
+		duplicate := false
+		for _, q := range points {
+			if q.x == p.x {
+				if string(q.y) != string(p.y) {
+					return nil, ErrMismatch
+				}
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			points = append(points, p)
+		}
+	}
+
+	if len(points) == 0 || len(points) < int(points[0].threshold) {
+		return nil, ErrTooFew
+	}
+	points = points[:points[0].threshold]
+
+	// Lagrange interpolation at zero, the basis polynomials being the same
+	// for every byte.
+	basis := make([]byte, len(points))
+	for i, p := range points {
+		basis[i] = 1
+		for j, q := range points {
+			if i != j {
+				basis[i] = gf256Mul(basis[i], gf256Div(q.x, p.x^q.x))
+			}
+		}
+	}
+
+	value := make([]byte, len(points[0].y))
+	for j := range value {
+		for i, p := range points {
+			value[j] ^= gf256Mul(basis[i], p.y[j])
+		}
+	}
+
+	secret := value[:len(value)-hashLen]
+	sum := sha256.Sum256(secret)
+	if subtle.ConstantTimeCompare(sum[:hashLen], value[len(secret):]) != 1 {
+		zrockford32.Zeroize(value)
+		return nil, ErrInvalid
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,119 @@
+package shamir_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+	"github.com/checksum0/go-zrockford32/shamir"
+)
+
+func TestSplitVector(t *testing.T) {
+	seed := bytes.NewReader(bytes.Repeat([]byte("0123456789abcdef"), 8))
+	shares, err := shamir.Split([]byte("hello, world"), 3, 2, shamir.WithRand(seed))
+	if err != nil {
+		t.Fatalf("Split: error: %v", err)
+	}
+
+	for i, e := range []string{
+		"share_YRBY-NCBT-GE32-AWN4-MPMT-K0E2-B0MY-1YT3-9PG8-48VE-808",
+		"share_YRBY-RCBT-GE30-YDAY-YEX2-7A2V-2G7K-PKDJ-2YPN-03XY-GSN",
+		"share_YRBY-GCBT-GE3V-EQTS-GWVS-XY6T-3MXC-8V13-VRWB-S6PT-SZC",
+	} {
+		if g := shares[i]; g != e {
+			t.Errorf("Split share %d wrong result: %q != %q", i, g, e)
+		}
+	}
+}
+
+func TestCombineSubsets(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+	shares, err := shamir.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split: error: %v", err)
+	}
+
+	for a := 0; a < 5; a++ {
+		for b := a + 1; b < 5; b++ {
+			for c := b + 1; c < 5; c++ {
+				combined, err := shamir.Combine(shares[c], shares[a], shares[b])
+				if err != nil {
+					t.Errorf("Combine %d %d %d: error: %v", a, b, c, err)
+					continue
+				}
+				if !bytes.Equal(combined, secret) {
+					t.Errorf("Combine %d %d %d wrong result: %q", a, b, c, combined)
+				}
+			}
+		}
+	}
+
+	combined, err := shamir.Combine(shares...)
+	if err != nil || !bytes.Equal(combined, secret) {
+		t.Errorf("Combine all wrong result: %q, %v", combined, err)
+	}
+}
+
+func TestCombineTolerant(t *testing.T) {
+	shares, _ := shamir.Split([]byte("hello, world"), 3, 2)
+
+	combined, err := shamir.Combine(strings.ToLower(shares[0]), strings.ReplaceAll(shares[2], "-", "")+"\n", shares[0])
+	if err != nil || string(combined) != "hello, world" {
+		t.Errorf("Combine wrong result: %q, %v", combined, err)
+	}
+}
+
+func TestCombineBad(t *testing.T) {
+	shares, _ := shamir.Split([]byte("hello, world"), 3, 2)
+	others, _ := shamir.Split([]byte("hello, world"), 3, 2)
+	longer, _ := shamir.Split([]byte("hello, world!"), 3, 2)
+	higher, _ := shamir.Split([]byte("hello, world"), 3, 3)
+
+	typo := shares[1][:len(shares[1])-1] + "Y"
+	if strings.HasSuffix(shares[1], "Y") {
+		typo = shares[1][:len(shares[1])-1] + "B"
+	}
+
+	for _, tc := range []struct {
+		shares []string
+		err    error
+	}{
+		{nil, shamir.ErrTooFew},
+		{shares[:1], shamir.ErrTooFew},
+		{[]string{shares[0], shares[0]}, shamir.ErrTooFew},
+		{higher[:2], shamir.ErrTooFew},
+		{[]string{shares[0], others[1]}, shamir.ErrMismatch},
+		{[]string{shares[0], longer[1]}, shamir.ErrMismatch},
+		{[]string{shares[0], higher[1]}, shamir.ErrMismatch},
+		{[]string{shares[0], typo}, zrockford32.ErrChecksum},
+		{[]string{shares[0], "seal_ZNKCX7"}, zrockford32.ErrChecksum},
+	} {
+		if _, err := shamir.Combine(tc.shares...); err != tc.err {
+			t.Errorf("Combine %q: wrong error: %v != %v", tc.shares, err, tc.err)
+		}
+	}
+}
+
+func TestCombineDetectsForgedShare(t *testing.T) {
+	seed := bytes.NewReader(bytes.Repeat([]byte{7}, 64))
+	shares, _ := shamir.Split([]byte("hello, world"), 3, 2, shamir.WithRand(seed))
+
+	// A share with the right header but other values decodes to another
+	// secret, which the embedded hash rejects.
+	_, payload, _ := zrockford32.StdEncoding.WithIgnoredChars("-").DecodePrefixed(shares[1])
+	payload[len(payload)-1] ^= 1
+	forged, _ := zrockford32.StdEncoding.EncodePrefixed(shamir.Prefix, payload)
+
+	if _, err := shamir.Combine(shares[0], forged); err != shamir.ErrInvalid {
+		t.Errorf("Combine: wrong error: %v", err)
+	}
+}
+
+func TestSplitBad(t *testing.T) {
+	for _, tc := range []struct{ n, k int }{{3, 1}, {2, 3}, {256, 2}, {0, 0}} {
+		if _, err := shamir.Split([]byte("secret"), tc.n, tc.k); err == nil {
+			t.Errorf("Split %d of %d: no error", tc.k, tc.n)
+		}
+	}
+}
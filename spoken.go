@@ -0,0 +1,133 @@
+package zrockford32
+
+import "strings"
+
+// Spoken codes
+//
+// Codes read over the phone are rendered as one word per symbol, whatever the
+// alphabet, so that they survive a poor line. The word for each symbol value
+// is the ICAO spelling word of its character in the standard alphabet, which
+// makes the spoken form of a standard code its usual phonetic spelling:
+// "YBND" is "yankee bravo november delta".
+
+var spokenWords = [32]string{
+	"yankee", "bravo", "november", "delta", "romeo", "foxtrot", "golf", "eight",
+	"echo", "juliett", "kilo", "mike", "charlie", "papa", "quebec", "xray",
+	"zero", "tango", "one", "victor", "whiskey", "two", "sierra", "zulu",
+	"alfa", "three", "four", "five", "hotel", "seven", "six", "nine",
+}
+
+// Other spellings heard for the same words.
+var spokenAliases = map[string]string{
+	"alpha":  "alfa",
+	"juliet": "juliett",
+	"whisky": "whiskey",
+	"tree":   "three",
+	"fower":  "four",
+	"fife":   "five",
+	"niner":  "nine",
+	"oh":     "zero",
+}
+
+var spokenValues = func() map[string]byte {
+	values := make(map[string]byte, len(spokenWords)+len(spokenAliases))
+	for i, word := range spokenWords {
+		values[word] = byte(i)
+	}
+	for alias, word := range spokenAliases {
+		values[alias] = values[word]
+	}
+
+	return values
+}()
+
+// ToSpoken returns the words for the symbols of code, separated by spaces.
+// Ignored characters are skipped.
+func (e *Encoding) ToSpoken(code string) (string, error) {
+	words := make([]string, 0, len(code))
+
+	for i := 0; i < len(code); i++ {
+		switch d := e.decodeMap[code[i]]; {
+		case d <= 31:
+			words = append(words, spokenWords[d])
+		case d != ignoredSymbol:
+			return "", CorruptInputError(i)
+		}
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// FromSpoken returns the code for words separated by spaces or commas, in any
+// case. Common alternative spellings such as "alpha", "x-ray" or "niner" are
+// accepted. An unknown word is reported as a CorruptInputError at its offset.
+func (e *Encoding) FromSpoken(words string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(words); {
+		if isSpokenSeparator(words[i]) {
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(words) && !isSpokenSeparator(words[j]) {
+			j++
+		}
+
+		word := strings.ReplaceAll(strings.ToLower(words[i:j]), "-", "")
+		d, ok := spokenValues[word]
+		if !ok {
+			return "", CorruptInputError(i)
+		}
+		b.WriteByte(e.encoder[d])
+		i = j
+	}
+
+	return b.String(), nil
+}
+
+func isSpokenSeparator(c byte) bool {
+	return c == ' ' || c == ',' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// Spelling
+
+var spellingWords = map[byte]string{
+	'-': "dash", '_': "underscore", '.': "dot", ' ': "space", '/': "slash",
+	':': "colon", '+': "plus", '=': "equals", '@': "at",
+}
+
+// Spell returns the phonetic spelling of s for reading it out, one word per
+// character: ICAO spelling words for letters, whatever their case, the names
+// of digits and of common punctuation. Other characters are kept as is.
+func Spell(s string) string {
+	words := make([]string, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		c := upper(s[i])
+		switch {
+		case 'A' <= c && c <= 'Z':
+			words = append(words, icaoLetters[c-'A'])
+		case '0' <= c && c <= '9':
+			words = append(words, digitNames[c-'0'])
+		case spellingWords[c] != "":
+			words = append(words, spellingWords[c])
+		default:
+			words = append(words, string(c))
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+var icaoLetters = [26]string{
+	"alfa", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliett", "kilo", "lima", "mike", "november", "oscar", "papa",
+	"quebec", "romeo", "sierra", "tango", "uniform", "victor", "whiskey", "xray",
+	"yankee", "zulu",
+}
+
+var digitNames = [10]string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+}
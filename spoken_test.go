@@ -0,0 +1,95 @@
+package zrockford32_test
+
+import (
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func TestToSpoken(t *testing.T) {
+	for _, tc := range []struct {
+		encoding *zrockford32.Encoding
+		code     string
+		spoken   string
+	}{
+		{zrockford32.StdEncoding, "", ""},
+		{zrockford32.StdEncoding, "YBND", "yankee bravo november delta"},
+		{zrockford32.StdEncoding, "6N9HQ", "six november nine hotel quebec"},
+		{zrockford32.LwrEncoding, "6n9hq", "six november nine hotel quebec"},
+		{zrockford32.StdEncoding.WithIgnoredChars("-"), "GTP-Y", "golf tango papa yankee"},
+	} {
+		spoken, err := tc.encoding.ToSpoken(tc.code)
+		if err != nil {
+			t.Errorf("ToSpoken %q: error: %v", tc.code, err)
+			continue
+		}
+		if g, e := spoken, tc.spoken; g != e {
+			t.Errorf("ToSpoken %q wrong result: %q != %q", tc.code, g, e)
+		}
+
+		code, err := tc.encoding.FromSpoken(spoken)
+		if err != nil {
+			t.Errorf("FromSpoken %q: error: %v", spoken, err)
+			continue
+		}
+		if g, e := code, zrockford32.Ungroup(tc.code, "-"); g != e {
+			t.Errorf("FromSpoken %q wrong result: %q != %q", spoken, g, e)
+		}
+	}
+}
+
+func TestToSpokenAllSymbols(t *testing.T) {
+	const alphabet = "YBNDRFG8EJKMCPQX0T1VW2SZA345H769"
+
+	spoken, err := zrockford32.StdEncoding.ToSpoken(alphabet)
+	if err != nil {
+		t.Fatalf("ToSpoken: error: %v", err)
+	}
+	if g, e := spoken, zrockford32.Spell(alphabet); g != e {
+		t.Errorf("ToSpoken differs from Spell: %q != %q", g, e)
+	}
+}
+
+func TestFromSpokenVariants(t *testing.T) {
+	code, err := zrockford32.StdEncoding.FromSpoken("  Alpha, X-RAY juliet\tniner  tree,fife whisky OH ")
+	if err != nil {
+		t.Fatalf("FromSpoken: error: %v", err)
+	}
+	if g, e := code, "AXJ935W0"; g != e {
+		t.Errorf("FromSpoken wrong result: %q != %q", g, e)
+	}
+}
+
+func TestSpokenBad(t *testing.T) {
+	if _, err := zrockford32.StdEncoding.ToSpoken("YB!D"); err != zrockford32.CorruptInputError(2) {
+		t.Errorf("ToSpoken: wrong error: %v", err)
+	}
+	for _, tc := range []struct {
+		words  string
+		offset int64
+	}{
+		{"yankee lima delta", 7},
+		{"bravo, india", 7},
+		{"yankeebravo", 0},
+	} {
+		if _, err := zrockford32.StdEncoding.FromSpoken(tc.words); err != zrockford32.CorruptInputError(tc.offset) {
+			t.Errorf("FromSpoken %q: wrong error: %v", tc.words, err)
+		}
+	}
+}
+
+func TestSpell(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		output string
+	}{
+		{"", ""},
+		{"YBND", "yankee bravo november delta"},
+		{"inv_8x-0", "india november victor underscore eight xray dash zero"},
+		{"a!", "alfa !"},
+	} {
+		if g, e := zrockford32.Spell(tc.input), tc.output; g != e {
+			t.Errorf("Spell %q wrong result: %q != %q", tc.input, g, e)
+		}
+	}
+}
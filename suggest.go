@@ -0,0 +1,231 @@
+package zrockford32
+
+import (
+	"sort"
+	"strings"
+)
+
+// Suggestions
+//
+// Suggest proposes the codes a mistyped one was most likely meant to be. Each
+// candidate is one edit away from the input, after letter case and lookalike
+// characters outside of the alphabet have been normalized, and edits between
+// symbols that look or sound alike are ranked first.
+
+type SuggestOptions struct {
+	// CheckSymbol only keeps candidates ending with a valid check symbol.
+	CheckSymbol bool
+	// Valid, when set, only keeps candidates it accepts. Without it nor
+	// CheckSymbol, any canonical code is a candidate, which is rarely useful.
+	Valid func(code string) bool
+	// Max limits the number of candidates returned, all of them when zero.
+	Max int
+}
+
+const (
+	costCase       = 1
+	costVisual     = 2
+	costPhonetic   = 3
+	costTranspose  = 2
+	costSubstitute = 6
+	costDouble     = 4
+	costInsert     = 6
+	costDelete     = 6
+	costDeleteBad  = 2
+)
+
+// Groups of characters often mistaken for each other, read or written.
+var visualGroups = []string{
+	"0ODQ", "1ILT7", "2Z", "5S", "8B3", "6G", "9GQ", "UVWY", "MNH", "EF", "KX",
+	"PR", "CG", "A4", "Z7",
+}
+
+// Groups of characters often mistaken for each other, heard over the phone.
+var phoneticGroups = []string{
+	"BCDEGPTVZ3", "AJK8", "FSX", "MN", "59YI",
+}
+
+func confusionCost(a, b byte) int {
+	a, b = upper(a), upper(b)
+
+	for _, group := range visualGroups {
+		if strings.IndexByte(group, a) >= 0 && strings.IndexByte(group, b) >= 0 {
+			return costVisual
+		}
+	}
+	for _, group := range phoneticGroups {
+		if strings.IndexByte(group, a) >= 0 && strings.IndexByte(group, b) >= 0 {
+			return costPhonetic
+		}
+	}
+
+	return costSubstitute
+}
+
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+
+	return c
+}
+
+func lower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c - 'A' + 'a'
+	}
+
+	return c
+}
+
+func (e *Encoding) isSymbol(c byte) bool {
+	return e.decodeMap[c] < 32
+}
+
+// normalize maps characters outside of the alphabet to the symbol of the other
+// letter case, or else to a lookalike symbol, and returns the cost of doing so.
+func (e *Encoding) normalize(s string) (string, int) {
+	normalized := []byte(s)
+	cost := 0
+
+	for i, c := range normalized {
+		if e.isSymbol(c) {
+			continue
+		}
+
+		if other, ok := e.caseVariant(c); ok {
+			normalized[i] = other
+			cost += costCase
+		} else if other, ok := e.lookalike(c); ok {
+			normalized[i] = other
+			cost += costVisual
+		}
+	}
+
+	return string(normalized), cost
+}
+
+func (e *Encoding) caseVariant(c byte) (byte, bool) {
+	if other := upper(c); e.isSymbol(other) {
+		return other, true
+	}
+	if other := lower(c); e.isSymbol(other) {
+		return other, true
+	}
+
+	return 0, false
+}
+
+func (e *Encoding) lookalike(c byte) (byte, bool) {
+	for _, group := range visualGroups {
+		if strings.IndexByte(group, upper(c)) < 0 {
+			continue
+		}
+
+		for i := 0; i < len(group); i++ {
+			if e.isSymbol(group[i]) {
+				return group[i], true
+			}
+			if other, ok := e.caseVariant(group[i]); ok {
+				return other, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func (e *Encoding) Suggest(s string, opts SuggestOptions) []string {
+	normalized, base := e.normalize(s)
+	candidates := make(map[string]int)
+
+	add := func(candidate string, cost int) {
+		if candidate == s {
+			return
+		}
+		if previous, ok := candidates[candidate]; !ok || cost < previous {
+			candidates[candidate] = cost
+		}
+	}
+
+	add(normalized, base)
+
+	for i := 0; i < len(normalized); i++ {
+		c := normalized[i]
+
+		// Substitutions.
+		for j := 0; j < len(e.encoder); j++ {
+			if symbol := e.encoder[j]; symbol != c {
+				add(normalized[:i]+string(symbol)+normalized[i+1:], base+confusionCost(c, symbol))
+			}
+		}
+
+		// Transpositions.
+		if i+1 < len(normalized) && normalized[i+1] != c {
+			add(normalized[:i]+string(normalized[i+1])+string(c)+normalized[i+2:], base+costTranspose)
+		}
+
+		// Deletions, cheap for characters which cannot be part of a code.
+		if e.isSymbol(c) {
+			add(normalized[:i]+normalized[i+1:], base+costDelete)
+		} else {
+			add(normalized[:i]+normalized[i+1:], base+costDeleteBad)
+		}
+	}
+
+	// Insertions, a symbol typed once instead of twice being the likeliest.
+	for i := 0; i <= len(normalized); i++ {
+		for j := 0; j < len(e.encoder); j++ {
+			symbol := e.encoder[j]
+			cost := costInsert
+			if (i > 0 && normalized[i-1] == symbol) || (i < len(normalized) && normalized[i] == symbol) {
+				cost = costDouble
+			}
+			add(normalized[:i]+string(symbol)+normalized[i:], base+cost)
+		}
+	}
+
+	suggestions := make([]string, 0, len(candidates))
+	for candidate := range candidates {
+		if e.acceptable(candidate, opts) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if candidates[a] != candidates[b] {
+			return candidates[a] < candidates[b]
+		}
+		return a < b
+	})
+
+	if opts.Max > 0 && len(suggestions) > opts.Max {
+		suggestions = suggestions[:opts.Max]
+	}
+
+	return suggestions
+}
+
+func (e *Encoding) acceptable(candidate string, opts SuggestOptions) bool {
+	if len(candidate) == 0 {
+		return false
+	}
+
+	payload := candidate
+	if opts.CheckSymbol {
+		var err error
+		if payload, err = e.VerifyCheckSymbol(candidate); err != nil {
+			return false
+		}
+	}
+
+	if opts.Valid != nil {
+		if _, err := e.DecodeString(candidate); err != nil {
+			return false
+		}
+		return opts.Valid(candidate)
+	}
+
+	return e.IsCanonical(payload)
+}
@@ -0,0 +1,73 @@
+package zrockford32_test
+
+import (
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+func TestSuggestCheckSymbol(t *testing.T) {
+	code, _ := zrockford32.StdEncoding.AddCheckSymbol("6N9HQ4T7YE")
+
+	for _, typo := range []string{
+		code[:2] + "G" + code[3:],
+		code[:1] + code[2:3] + code[1:2] + code[3:],
+		code[:3] + code[3:4] + code[3:],
+		code[:3] + code[4:],
+		"6n9hq4t7ye" + code[10:],
+	} {
+		// A single check symbol cannot tell apart every candidate, the
+		// intended code must be among the cheapest ones though.
+		suggestions := zrockford32.StdEncoding.Suggest(typo, zrockford32.SuggestOptions{CheckSymbol: true, Max: 10})
+		found := false
+		for _, suggestion := range suggestions {
+			found = found || suggestion == code
+		}
+		if !found {
+			t.Errorf("Suggest %q wrong result: %q, expected %q", typo, suggestions, code)
+		}
+	}
+}
+
+func TestSuggestLookalikes(t *testing.T) {
+	code, _ := zrockford32.StdEncoding.AddCheckSymbol("AB3SR12X8FHFNVZAE075FKN3A7XH8VDK6JS22K0")
+	typo := "AB3SRI2X8FHFNVZAE O75" + code[20:]
+
+	suggestions := zrockford32.StdEncoding.Suggest(typo, zrockford32.SuggestOptions{CheckSymbol: true, Max: 3})
+	if len(suggestions) == 0 || suggestions[0] != code {
+		t.Errorf("Suggest %q wrong result: %q, expected %q first", typo, suggestions, code)
+	}
+	if len(suggestions) > 3 {
+		t.Errorf("Suggest returned more than Max: %q", suggestions)
+	}
+}
+
+func TestSuggestValid(t *testing.T) {
+	known := map[string]bool{"YBNDRFG8": true, "YBNDRFGE": true, "PB1SA5DX": true}
+	opts := zrockford32.SuggestOptions{Valid: func(code string) bool { return known[code] }}
+
+	for _, tc := range []struct {
+		typo        string
+		suggestions []string
+	}{
+		{"YBNDRFGB", []string{"YBNDRFG8", "YBNDRFGE"}},
+		{"YBNDRFG", []string{"YBNDRFG8", "YBNDRFGE"}},
+		{"YBNDRFG88", []string{"YBNDRFG8"}},
+		{"YBNDFRG8", []string{"YBNDRFG8"}},
+		{"pb1sa5dx", []string{"PB1SA5DX"}},
+		{"PB1SA5DX", nil},
+		{"Q7ZZR5DR", nil},
+	} {
+		suggestions := zrockford32.StdEncoding.Suggest(tc.typo, opts)
+		if len(suggestions) != len(tc.suggestions) {
+			t.Errorf("Suggest %q wrong result: %q != %q", tc.typo, suggestions, tc.suggestions)
+			continue
+		}
+		for i := range suggestions {
+			if suggestions[i] != tc.suggestions[i] {
+				t.Errorf("Suggest %q wrong result: %q != %q", tc.typo, suggestions, tc.suggestions)
+				break
+			}
+		}
+	}
+}
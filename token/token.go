@@ -0,0 +1,236 @@
+// Package token issues typed API tokens such as "inv_PB1SA5DX...", made of a
+// prefix naming the kind of token and random zrockford32 data followed by a
+// checksum, in the prefixed code format of zrockford32.
+//
+// The random data is split into a public ID, which servers can store and look
+// up in clear, and a secret, of which they only store a hash and which Verify
+// compares in constant time. The checksum lets tokens be validated offline and
+// lets Scan find leaked tokens in text with few false positives.
+package token
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/checksum0/go-zrockford32"
+)
+
+var ErrInvalid = errors.New("invalid token")
+
+const maxGenerateAttempts = 100
+
+type Format struct {
+	prefix      string
+	encoding    *zrockford32.Encoding
+	rand        io.Reader
+	idBytes     int
+	secretBytes int
+	blocklist   *zrockford32.Blocklist
+	pattern     *regexp.Regexp
+}
+
+type Option func(*Format)
+
+func WithEncoding(encoding *zrockford32.Encoding) Option {
+	return func(f *Format) {
+		f.encoding = encoding
+	}
+}
+
+// WithRand sets the source of randomness of Generate, crypto/rand by default.
+func WithRand(r io.Reader) Option {
+	return func(f *Format) {
+		f.rand = r
+	}
+}
+
+// WithIDBytes sets the length of the public ID, 10 bytes by default.
+func WithIDBytes(n int) Option {
+	return func(f *Format) {
+		f.idBytes = n
+	}
+}
+
+// WithSecretBytes sets the length of the secret, 20 bytes by default and at
+// least 16.
+func WithSecretBytes(n int) Option {
+	return func(f *Format) {
+		f.secretBytes = n
+	}
+}
+
+// WithBlocklist makes Generate reject and regenerate tokens whose code after
+// the prefix is matched by blocklist.
+func WithBlocklist(blocklist *zrockford32.Blocklist) Option {
+	return func(f *Format) {
+		f.blocklist = blocklist
+	}
+}
+
+func New(prefix string, opts ...Option) (*Format, error) {
+	f := &Format{
+		prefix:      prefix,
+		encoding:    zrockford32.StdEncoding,
+		rand:        rand.Reader,
+		idBytes:     10,
+		secretBytes: 20,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	if err := zrockford32.ValidatePrefix(prefix); err != nil {
+		return nil, err
+	}
+	if f.idBytes < 1 {
+		return nil, errors.New("token ID must be at least 1 byte")
+	}
+	if f.secretBytes < 16 {
+		return nil, errors.New("token secret must be at least 16 bytes")
+	}
+
+	var class strings.Builder
+	for _, c := range []byte(f.encoding.Alphabet()) {
+		if !isWordByte(c) {
+			class.WriteByte('\\')
+		}
+		class.WriteByte(c)
+	}
+	f.pattern = regexp.MustCompile(regexp.QuoteMeta(prefix+string(zrockford32.PrefixSeparator)) + "[" + class.String() + "]{" + strconv.Itoa(f.Len()-len(prefix)-1) + "}")
+
+	return f, nil
+}
+
+// Len returns the length of the tokens of the format.
+func (f *Format) Len() int {
+	return len(f.prefix) + 1 + ((f.idBytes+f.secretBytes)*8+4)/5 + 6
+}
+
+// Regexp returns a pattern matching the tokens of the format, for use by
+// secret scanners. Matches still need their checksum verified, as Scan does.
+func (f *Format) Regexp() *regexp.Regexp {
+	return f.pattern
+}
+
+type Token struct {
+	// ID is the public part of the token, suitable as a database key.
+	ID string
+
+	secret []byte
+	text   string
+}
+
+func (f *Format) Generate() (*Token, error) {
+	data := make([]byte, f.idBytes+f.secretBytes)
+
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		if _, err := io.ReadFull(f.rand, data); err != nil {
+			return nil, err
+		}
+
+		text, err := f.encoding.EncodePrefixed(f.prefix, data)
+		if err != nil {
+			return nil, err
+		}
+
+		if !f.blocklist.Match(text[len(f.prefix)+1:]) {
+			return f.token(data, text), nil
+		}
+	}
+
+	return nil, errors.New("could not generate a token outside of the blocklist")
+}
+
+// Parse validates s offline, without telling whether the token was ever
+// issued.
+func (f *Format) Parse(s string) (*Token, error) {
+	if len(s) != f.Len() {
+		return nil, ErrInvalid
+	}
+
+	prefix, data, err := f.encoding.DecodePrefixed(s)
+	if err != nil || prefix != f.prefix || len(data) != f.idBytes+f.secretBytes {
+		return nil, ErrInvalid
+	}
+
+	return f.token(data, s), nil
+}
+
+func (f *Format) token(data []byte, text string) *Token {
+	return &Token{
+		ID:     f.encoding.EncodeToString(data[:f.idBytes]),
+		secret: data[f.idBytes:],
+		text:   text,
+	}
+}
+
+// String returns the whole token, secret included.
+func (t *Token) String() string {
+	return t.text
+}
+
+// SecretHash returns the SHA-256 hash of the secret, which is what servers
+// should store next to the ID.
+func (t *Token) SecretHash() []byte {
+	sum := sha256.Sum256(t.secret)
+	return sum[:]
+}
+
+// Verify reports whether the secret of t matches a hash returned by
+// SecretHash, in constant time.
+func (t *Token) Verify(hash []byte) bool {
+	return subtle.ConstantTimeCompare(t.SecretHash(), hash) == 1
+}
+
+// Scanning
+
+type Finding struct {
+	Token *Token
+
+	// Line and Column locate the token in the input, counting from 1, with
+	// columns in bytes.
+	Line   int
+	Column int
+}
+
+// Scan returns the valid tokens of the format found in r. Candidates must not
+// be surrounded by other letters, digits or underscores, and must have a valid
+// checksum.
+func (f *Format) Scan(r io.Reader) ([]Finding, error) {
+	var findings []Finding
+	reader := bufio.NewReader(r)
+
+	for n := 1; ; n++ {
+		line, err := reader.ReadString('\n')
+		if len(line) == 0 && err == io.EOF {
+			return findings, nil
+		} else if err != nil && err != io.EOF {
+			return findings, err
+		}
+
+		for _, match := range f.pattern.FindAllStringIndex(line, -1) {
+			start, end := match[0], match[1]
+			if start > 0 && isWordByte(line[start-1]) || end < len(line) && isWordByte(line[end]) {
+				continue
+			}
+
+			token, err := f.Parse(line[start:end])
+			if err != nil {
+				continue
+			}
+
+			findings = append(findings, Finding{Token: token, Line: n, Column: start + 1})
+		}
+	}
+}
+
+func isWordByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
@@ -0,0 +1,170 @@
+package token_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/checksum0/go-zrockford32"
+	"github.com/checksum0/go-zrockford32/token"
+)
+
+func newFormat(t *testing.T, prefix string) *token.Format {
+	t.Helper()
+
+	seed := bytes.Repeat([]byte("0123456789abcdefghijklmnopqrstuv"), 8)
+	f, err := token.New(prefix, token.WithRand(bytes.NewReader(seed)))
+	if err != nil {
+		t.Fatalf("New: error: %v", err)
+	}
+
+	return f
+}
+
+func TestGenerate(t *testing.T) {
+	f := newFormat(t, "inv")
+
+	tok, err := f.Generate()
+	if err != nil {
+		t.Fatalf("Generate: error: %v", err)
+	}
+	if g, e := tok.String(), "inv_GYAVRC3WGW5DQQB3CFTGG3DFC3VS04MKPPSG45VXQBAZRH5W2FV8EJ"; g != e {
+		t.Errorf("Generate wrong token: %q != %q", g, e)
+	}
+	if g, e := tok.ID, "GYAVRC3WGW5DQQB3"; g != e {
+		t.Errorf("Generate wrong ID: %q != %q", g, e)
+	}
+	if g, e := len(tok.String()), f.Len(); g != e {
+		t.Errorf("Generate wrong length: %d != %d", g, e)
+	}
+	if !f.Regexp().MatchString(tok.String()) {
+		t.Errorf("Regexp does not match %q", tok)
+	}
+}
+
+func TestGenerateBlocklist(t *testing.T) {
+	// The first 30 bytes encode to Y symbols only, the next to 9 symbols.
+	seed := append(make([]byte, 30), bytes.Repeat([]byte{0xFF}, 30)...)
+	f, err := token.New("inv",
+		token.WithRand(bytes.NewReader(seed)),
+		token.WithBlocklist(zrockford32.NewBlocklist("yyyy")),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tok, err := f.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if g, e := tok.ID, "9999999999999999"; g != e {
+		t.Errorf("Generate did not skip the blocked token: got ID %q, expected %q", g, e)
+	}
+
+	blockAll := zrockford32.NewBlocklist(strings.Split("ybndrfg8ejkmcpqx0t1vw2sza345h769", "")...)
+	f, err = token.New("inv", token.WithBlocklist(blockAll))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := f.Generate(); err == nil {
+		t.Errorf("Generate with exhaustive blocklist succeeded")
+	}
+}
+
+func TestParse(t *testing.T) {
+	f := newFormat(t, "inv")
+	issued, _ := f.Generate()
+	hash := issued.SecretHash()
+
+	tok, err := f.Parse(issued.String())
+	if err != nil {
+		t.Fatalf("Parse %q: error: %v", issued, err)
+	}
+	if tok.ID != issued.ID || !tok.Verify(hash) {
+		t.Errorf("Parse %q wrong result: %q", issued, tok.ID)
+	}
+
+	other, _ := f.Generate()
+	if other.Verify(hash) {
+		t.Errorf("Verify accepted the secret of another token")
+	}
+}
+
+func TestParseBad(t *testing.T) {
+	f := newFormat(t, "inv")
+	tok, _ := f.Generate()
+	s := tok.String()
+
+	orders := newFormat(t, "ord")
+	order, _ := orders.Generate()
+
+	for _, bad := range []string{
+		"",
+		s[:len(s)-1],
+		s + "Y",
+		s[:10] + "Y" + s[11:],
+		strings.ToLower(s),
+		order.String(),
+		"inx" + s[3:],
+	} {
+		if _, err := f.Parse(bad); err != token.ErrInvalid {
+			t.Errorf("Parse %q: wrong error: %v", bad, err)
+		}
+	}
+}
+
+func TestNewBad(t *testing.T) {
+	for _, tc := range []struct {
+		prefix string
+		opts   []token.Option
+	}{
+		{"", nil},
+		{"in_v", nil},
+		{"inv", []token.Option{token.WithIDBytes(0)}},
+		{"inv", []token.Option{token.WithSecretBytes(15)}},
+	} {
+		if _, err := token.New(tc.prefix, tc.opts...); err == nil {
+			t.Errorf("New %q: no error", tc.prefix)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	f := newFormat(t, "inv")
+	first, _ := f.Generate()
+	second, _ := f.Generate()
+	broken := first.String()[:20] + "Y" + first.String()[21:]
+	if broken == first.String() {
+		broken = first.String()[:20] + "B" + first.String()[21:]
+	}
+
+	input := "config:\n" +
+		"  key: " + first.String() + "\n" +
+		"  old: " + broken + "\n" +
+		"  embedded: x" + second.String() + "\n" +
+		"export KEY=\"" + second.String() + "\"\n" +
+		second.String()
+
+	findings, err := f.Scan(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Scan: error: %v", err)
+	}
+
+	want := []struct {
+		id     string
+		line   int
+		column int
+	}{
+		{first.ID, 2, 8},
+		{second.ID, 5, 13},
+		{second.ID, 6, 1},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("Scan wrong findings: %+v", findings)
+	}
+	for i, w := range want {
+		if g := findings[i]; g.Token.ID != w.id || g.Line != w.line || g.Column != w.column {
+			t.Errorf("Scan finding %d wrong result: %s %d:%d != %s %d:%d", i, g.Token.ID, g.Line, g.Column, w.id, w.line, w.column)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io"
 	"strconv"
+	"strings"
 )
 
 // Encodings
@@ -11,17 +12,28 @@ import (
 const encodeStd = "YBNDRFG8EJKMCPQX0T1VW2SZA345H769"
 const encodeLwr = "ybndrfg8ejkmcpqx0t1vw2sza345h769"
 
+const (
+	invalidSymbol = 0xFF
+	ignoredSymbol = 0xFE
+)
+
 type Encoding struct {
-	encoder   string
-	decodeMap [256]byte
+	encoder      string
+	decodeMap    [256]byte
+	constantTime bool
 }
 
+// NewEncoding panics if encoder is not a valid alphabet, see ValidateAlphabet.
 func NewEncoding(encoder string) *Encoding {
+	if err := ValidateAlphabet(encoder); err != nil {
+		panic("zrockford32: " + err.Error())
+	}
+
 	e := new(Encoding)
 	e.encoder = encoder
 
 	for i := 0; i < len(e.decodeMap); i++ {
-		e.decodeMap[i] = 0xFF
+		e.decodeMap[i] = invalidSymbol
 	}
 
 	for i := 0; i < len(encoder); i++ {
@@ -31,9 +43,74 @@ func NewEncoding(encoder string) *Encoding {
 	return e
 }
 
+// ValidateAlphabet reports whether encoder can be used with NewEncoding: it
+// must hold 32 distinct printable ASCII characters.
+func ValidateAlphabet(encoder string) error {
+	if len(encoder) != 32 {
+		return errors.New("alphabet must be 32 characters long, not " + strconv.Itoa(len(encoder)))
+	}
+
+	var seen [256]bool
+	for i := 0; i < len(encoder); i++ {
+		c := encoder[i]
+		if c <= ' ' || c > '~' {
+			return errors.New("alphabet contains invalid character " + strconv.QuoteRune(rune(c)))
+		}
+		if seen[c] {
+			return errors.New("alphabet contains duplicate character " + strconv.QuoteRune(rune(c)))
+		}
+		seen[c] = true
+	}
+
+	return nil
+}
+
 var StdEncoding = NewEncoding(encodeStd)
 var LwrEncoding = NewEncoding(encodeLwr)
 
+func (e *Encoding) Alphabet() string {
+	return e.encoder
+}
+
+// WithIgnoreCase returns a copy of e which also decodes letters of the other
+// case, unless that letter is already part of the alphabet.
+func (e *Encoding) WithIgnoreCase() *Encoding {
+	c := *e
+
+	for i := 0; i < len(e.encoder); i++ {
+		other := e.encoder[i]
+		switch {
+		case 'a' <= other && other <= 'z':
+			other -= 'a' - 'A'
+		case 'A' <= other && other <= 'Z':
+			other += 'a' - 'A'
+		default:
+			continue
+		}
+
+		if c.decodeMap[other] == invalidSymbol {
+			c.decodeMap[other] = byte(i)
+		}
+	}
+
+	return &c
+}
+
+// WithIgnoredChars returns a copy of e which skips chars when decoding, such as
+// group separators or line breaks. It panics if chars overlaps the alphabet.
+func (e *Encoding) WithIgnoredChars(chars string) *Encoding {
+	c := *e
+
+	for i := 0; i < len(chars); i++ {
+		if strings.IndexByte(e.encoder, chars[i]) >= 0 {
+			panic("zrockford32: ignored character is part of the alphabet")
+		}
+		c.decodeMap[chars[i]] = ignoredSymbol
+	}
+
+	return &c
+}
+
 func (e *Encoding) encode(dst, src []byte, bits int) int {
 	off := 0
 
@@ -59,7 +136,11 @@ func (e *Encoding) encode(dst, src []byte, bits int) int {
 			char &= 255 << uint((i+5)-bits)
 		}
 
-		dst[off] = e.encoder[char]
+		if e.constantTime {
+			dst[off] = e.ctSymbol(char)
+		} else {
+			dst[off] = e.encoder[char]
+		}
 		off++
 
 		if offset > 2 {
@@ -184,7 +265,13 @@ func (e CorruptInputError) Error() string {
 	return "illegal zrockford32 data at input byte " + strconv.FormatInt(int64(e), 10)
 }
 
-func (e *Encoding) decode(dst, src []byte, bits int) (int, error) {
+// decode accepts strings as well as byte slices, so that decoding a string
+// does not copy it.
+func decode[S string | []byte](e *Encoding, dst []byte, src S, bits int) (int, error) {
+	if e.constantTime {
+		return decodeConstantTime(e, dst, src, bits)
+	}
+
 	offlen := len(src)
 	off := 0
 
@@ -192,59 +279,80 @@ func (e *Encoding) decode(dst, src []byte, bits int) (int, error) {
 		var dbuffer [8]byte
 
 		j := 0
-		for ; j < 8; j++ {
-			if len(src) == 0 {
-				break
-			}
-
+		for j < 8 && len(src) > 0 {
 			in := src[0]
 			src = src[1:]
 			dbuffer[j] = e.decodeMap[in]
-			if dbuffer[j] == 0xFF {
+			if dbuffer[j] == ignoredSymbol {
+				dbuffer[j] = 0
+				continue
+			}
+			if dbuffer[j] == invalidSymbol {
 				return off, CorruptInputError(offlen - len(src) - 1)
 			}
+			j++
 		}
 
-		dst[off+0] = dbuffer[0]<<3 | dbuffer[1]>>2
-		dst[off+1] = dbuffer[1]<<6 | dbuffer[2]<<1 | dbuffer[3]>>4
-		dst[off+2] = dbuffer[3]<<4 | dbuffer[4]>>1
-		dst[off+3] = dbuffer[4]<<7 | dbuffer[5]<<2 | dbuffer[6]>>3
-		dst[off+4] = dbuffer[6]<<5 | dbuffer[7]
-
-		if bits < 0 {
-			var lookup = []int{0, 1, 1, 2, 2, 3, 4, 4, 5}
-			off += lookup[j]
-			continue
+		if j == 0 {
+			break
 		}
 
-		bitsInBlock := bits
-		if bitsInBlock > 40 {
-			bitsInBlock = 40
+		n, ok := decodeBlock(dst[off:], &dbuffer, j, bits)
+		if !ok {
+			return off, io.ErrShortBuffer
 		}
-
-		off += (bitsInBlock + 7) / 8
+		off += n
 		bits -= 40
 	}
 
 	return off, nil
 }
 
+// decodeBlock decodes the j symbol values of dbuffer into dst, and returns the
+// number of bytes they hold, bits being the number of bits left to decode, or
+// negative to decode them all. Only these bytes are written to dst, and it is
+// not large enough if ok is false.
+func decodeBlock(dst []byte, dbuffer *[8]byte, j int, bits int) (n int, ok bool) {
+	if bits < 0 {
+		var lookup = []int{0, 1, 1, 2, 2, 3, 4, 4, 5}
+		n = lookup[j]
+	} else {
+		n = (min(bits, 40) + 7) / 8
+	}
+	if n > len(dst) {
+		return 0, false
+	}
+
+	block := [5]byte{
+		dbuffer[0]<<3 | dbuffer[1]>>2,
+		dbuffer[1]<<6 | dbuffer[2]<<1 | dbuffer[3]>>4,
+		dbuffer[3]<<4 | dbuffer[4]>>1,
+		dbuffer[4]<<7 | dbuffer[5]<<2 | dbuffer[6]>>3,
+		dbuffer[6]<<5 | dbuffer[7],
+	}
+	copy(dst, block[:n])
+	Zeroize(block[:])
+
+	return n, true
+}
+
 func (e *Encoding) DecodeBits(dst, src []byte, bits int) (int, error) {
 	if bits < 0 {
 		return 0, errors.New("cannot decode a negative bit count")
 	}
 
-	return e.decode(dst, src, bits)
+	return decode(e, dst, src, bits)
 }
 
 func (e *Encoding) Decode(dst, src []byte) (int, error) {
-	return e.decode(dst, src, -1)
+	return decode(e, dst, src, -1)
 }
 
 func (e *Encoding) decodeString(s string, bits int) ([]byte, error) {
 	dst := make([]byte, e.DecodedLen(len(s)))
-	n, err := e.decode(dst, []byte(s), bits)
+	n, err := decode(e, dst, s, bits)
 	if err != nil {
+		Zeroize(dst)
 		return nil, err
 	}
 
@@ -263,31 +371,108 @@ func (e *Encoding) DecodeString(s string) ([]byte, error) {
 	return e.decodeString(s, -1)
 }
 
+// IsCanonical reports whether s is exactly what Encode produces for the bytes
+// it decodes to, that is whether it neither has non-zero trailing bits nor a
+// length that Encode never produces. Ignored characters and letter case, when
+// the encoding accepts them, are not taken into account.
+func (e *Encoding) IsCanonical(s string) bool {
+	return e.isCanonical(s, -1)
+}
+
+func (e *Encoding) IsCanonicalBits(s string, bits int) bool {
+	if bits < 0 {
+		return false
+	}
+
+	return e.isCanonical(s, bits)
+}
+
+func (e *Encoding) isCanonical(s string, bits int) bool {
+	decoded, err := e.decodeString(s, bits)
+	if err != nil || len(decoded)*8 < bits {
+		return false
+	}
+
+	dst := make([]byte, e.EncodedLen(len(decoded)))
+	n := e.encode(dst, decoded, bits)
+
+	j := 0
+	for i := 0; i < len(s); i++ {
+		d := e.decodeMap[s[i]]
+		if d == ignoredSymbol {
+			continue
+		}
+		if j >= n || d != e.decodeMap[dst[j]] {
+			return false
+		}
+		j++
+	}
+
+	return j == n
+}
+
 type decoder struct {
 	io.ReadCloser
 	encoding *Encoding
 	reader   io.Reader
 	buffer   [1024]byte
 	nbuffer  int
+	symbols  [648]byte
+	nsymbols int
+	offset   int64
 	eof      bool
 	err      error
 }
 
+// fill decodes the next chunk of input into buffer. Symbols are validated and
+// ignored characters dropped before decoding, so that only whole blocks of 8
+// symbols are decoded before the end of the input, whatever the size of the
+// reads and the number of skipped characters.
+func (d *decoder) fill() error {
+	chunk := make([]byte, 640)
+	l, err := io.ReadFull(d.reader, chunk)
+	if io.EOF == err || io.ErrUnexpectedEOF == err {
+		d.eof = true
+	} else if err != nil {
+		return err
+	}
+
+	for i := 0; i < l; i++ {
+		switch d.encoding.decodeMap[chunk[i]] {
+		case ignoredSymbol:
+			continue
+		case invalidSymbol:
+			return CorruptInputError(d.offset + int64(i))
+		}
+		d.symbols[d.nsymbols] = chunk[i]
+		d.nsymbols++
+	}
+	d.offset += int64(l)
+
+	m := d.nsymbols
+	if !d.eof {
+		m -= m % 8
+	}
+
+	d.nbuffer, err = d.encoding.Decode(d.buffer[0:], d.symbols[:m])
+	if err != nil {
+		return err
+	}
+	d.nsymbols = copy(d.symbols[0:], d.symbols[m:d.nsymbols])
+
+	return nil
+}
+
 func (d *decoder) Read(p []byte) (int, error) {
 	var n int
 
-	if d.nbuffer < 1 && !d.eof {
-		buffer := make([]byte, 640)
-		l, err := d.reader.Read(buffer)
-		if io.EOF == err {
-			d.eof = true
-		} else if err != nil {
-			return n, err
-		}
+	if d.err != nil {
+		return n, d.err
+	}
 
-		d.nbuffer, err = d.encoding.Decode(d.buffer[0:], buffer[:l])
-		if err != nil {
-			return n, err
+	for d.nbuffer < 1 && !d.eof {
+		if d.err = d.fill(); d.err != nil {
+			return n, d.err
 		}
 	}
 
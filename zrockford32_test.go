@@ -3,7 +3,9 @@ package zrockford32_test
 import (
 	"bytes"
 	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/checksum0/go-zrockford32"
 )
@@ -460,3 +462,182 @@ func TestDecoderStd(t *testing.T) {
 		}
 	}
 }
+
+func TestDecoderShortReads(t *testing.T) {
+	for _, tc := range byteTestsStd {
+		dec := zrockford32.NewDecoder(zrockford32.StdEncoding, iotest.OneByteReader(strings.NewReader(tc.encoded)))
+		got, err := io.ReadAll(dec)
+		if err != nil {
+			t.Errorf("Decode %q: error: %v", tc.encoded, err)
+			continue
+		}
+		if g, e := got, tc.decoded; !bytes.Equal(g, e) {
+			t.Errorf("Decode %q wrong result: %x != %x", tc.encoded, g, e)
+		}
+	}
+}
+
+func TestDecoderCorruptOffset(t *testing.T) {
+	input := strings.Repeat("YBNDRFG8", 100) + "YB!D"
+	dec := zrockford32.NewDecoder(zrockford32.StdEncoding, strings.NewReader(input))
+	_, err := io.ReadAll(dec)
+	if err, ok := err.(zrockford32.CorruptInputError); !ok || err != 802 {
+		t.Errorf("wrong error from bad decode: %T: %v", err, err)
+	}
+}
+
+func TestValidateAlphabet(t *testing.T) {
+	for _, tc := range []struct {
+		alphabet string
+		valid    bool
+	}{
+		{"YBNDRFG8EJKMCPQX0T1VW2SZA345H769", true},
+		{"ybndrfg8ejkmcpqxot1uwisza345h769", true},
+		{"YBNDRFG8EJKMCPQX0T1VW2SZA345H76", false},
+		{"YBNDRFG8EJKMCPQX0T1VW2SZA345H7699", false},
+		{"YBNDRFG8EJKMCPQX0T1VW2SZA345H766", false},
+		{"YBNDRFG8EJKMCPQX0T1VW2SZA345H76\n", false},
+		{"YBNDRFG8EJKMCPQX0T1VW2SZA345H76 ", false},
+	} {
+		if err := zrockford32.ValidateAlphabet(tc.alphabet); (err == nil) != tc.valid {
+			t.Errorf("ValidateAlphabet %q wrong result: %v", tc.alphabet, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewEncoding did not panic")
+		}
+	}()
+	zrockford32.NewEncoding("YBND")
+}
+
+func TestDecodeIgnoreCase(t *testing.T) {
+	for _, enc := range []*zrockford32.Encoding{
+		zrockford32.StdEncoding.WithIgnoreCase(),
+		zrockford32.LwrEncoding.WithIgnoreCase(),
+	} {
+		for _, s := range []string{"PB1SA5DXF008Q551PT1YW", "pb1sa5dxf008q551pt1yw", "Pb1Sa5DxF008q551Pt1Yw"} {
+			b, err := enc.DecodeString(s)
+			if err != nil {
+				t.Errorf("DecodeString %q: error: %v", s, err)
+				continue
+			}
+			if g, e := string(b), "hello, world\n"; g != e {
+				t.Errorf("DecodeString %q wrong result: %q != %q", s, g, e)
+			}
+		}
+	}
+
+	// The original encoding is left untouched.
+	if _, err := zrockford32.StdEncoding.DecodeString("pb1sa5dx"); err == nil {
+		t.Errorf("StdEncoding decoded lowercase input")
+	}
+}
+
+func TestDecodeIgnoredChars(t *testing.T) {
+	enc := zrockford32.StdEncoding.WithIgnoredChars("- \r\n")
+	for _, tc := range []struct {
+		bits    int
+		encoded string
+		decoded []byte
+	}{
+		{-1, "PB1S-A5DX-F008-Q551-PT1Y-W\n", []byte("hello, world\n")},
+		{-1, "6N9HQ\r\n", []byte{240, 191, 199}},
+		{-1, "--9H--", []byte{0xff}},
+		{-1, "9999 9999 -", []byte{0xff, 0xff, 0xff, 0xff, 0xff}},
+		{-1, "--", []byte{}},
+		{24, "6N9-HQ", []byte{240, 191, 199}},
+		{48, "9999-9999-9H-", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	} {
+		var b []byte
+		var err error
+		if tc.bits < 0 {
+			b, err = enc.DecodeString(tc.encoded)
+		} else {
+			b, err = enc.DecodeBitsString(tc.encoded, tc.bits)
+		}
+		if err != nil {
+			t.Errorf("Decode %q: error: %v", tc.encoded, err)
+			continue
+		}
+		if g, e := b, tc.decoded; !bytes.Equal(g, e) {
+			t.Errorf("Decode %q wrong result: %x != %x", tc.encoded, g, e)
+		}
+	}
+
+	_, err := enc.DecodeString("PB1S-A5!X")
+	if err, ok := err.(zrockford32.CorruptInputError); !ok || err != 7 {
+		t.Errorf("wrong error from bad decode: %T: %v", err, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("WithIgnoredChars did not panic")
+		}
+	}()
+	zrockford32.StdEncoding.WithIgnoredChars("-Y")
+}
+
+func TestDecoderIgnoredChars(t *testing.T) {
+	enc := zrockford32.StdEncoding.WithIgnoredChars("-\n")
+	want := bytes.Repeat([]byte("hello, world\n"), 100)
+	input := zrockford32.Group(zrockford32.StdEncoding.EncodeToString(want), 5, "-") + "\n"
+
+	for _, r := range []io.Reader{strings.NewReader(input), iotest.OneByteReader(strings.NewReader(input))} {
+		got, err := io.ReadAll(zrockford32.NewDecoder(enc, r))
+		if err != nil {
+			t.Errorf("Decode: error: %v", err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Decode wrong result: %q", got)
+		}
+	}
+
+	bad := input[:700] + "!" + input[701:]
+	_, err := io.ReadAll(zrockford32.NewDecoder(enc, strings.NewReader(bad)))
+	if err, ok := err.(zrockford32.CorruptInputError); !ok || err != 700 {
+		t.Errorf("wrong error from bad decode: %T: %v", err, err)
+	}
+}
+
+func TestIsCanonical(t *testing.T) {
+	for _, tc := range byteTestsStd {
+		if !zrockford32.StdEncoding.IsCanonical(tc.encoded) {
+			t.Errorf("IsCanonical %q: false", tc.encoded)
+		}
+	}
+	for _, tc := range bitTestsStd {
+		if !zrockford32.StdEncoding.IsCanonicalBits(tc.encoded, tc.bits) {
+			t.Errorf("IsCanonicalBits %q %d bits: false", tc.encoded, tc.bits)
+		}
+	}
+
+	for _, s := range []string{"9B", "9", "999", "999999", "GT!Y"} {
+		if zrockford32.StdEncoding.IsCanonical(s) {
+			t.Errorf("IsCanonical %q: true", s)
+		}
+	}
+	for _, tc := range []struct {
+		encoded string
+		bits    int
+	}{
+		{"NYET", 18},
+		{"NYE", 20},
+		{"NYETY", 20},
+		{"NYET", -1},
+	} {
+		if zrockford32.StdEncoding.IsCanonicalBits(tc.encoded, tc.bits) {
+			t.Errorf("IsCanonicalBits %q %d bits: true", tc.encoded, tc.bits)
+		}
+	}
+
+	tolerant := zrockford32.StdEncoding.WithIgnoreCase().WithIgnoredChars("-")
+	if !tolerant.IsCanonical("gt-py") {
+		t.Errorf("IsCanonical with ignored characters: false")
+	}
+	if tolerant.IsCanonical("gt-pb") {
+		t.Errorf("IsCanonical with ignored characters: true")
+	}
+}